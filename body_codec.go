@@ -0,0 +1,563 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Media types which MediaType.Encode and MediaType.Decode handle specially.
+const (
+	FormURLEncoded    = "application/x-www-form-urlencoded"
+	MultipartFormData = "multipart/form-data"
+)
+
+// FilePart is a file in multipart body. Header is the additional headers
+// of the part, like the ones described in the encoding object.
+type FilePart struct {
+	Filename    string
+	ContentType string
+	Header      textproto.MIMEHeader
+	Content     []byte
+}
+
+// Part is a non-file value in multipart body with the headers of the part.
+// The header values are serialized with the header objects in the encoding
+// of the property, as Header.Serialize does.
+type Part struct {
+	Value  interface{}
+	Header map[string]interface{}
+}
+
+// Encode given value as the body of the content type, according to the
+// schema and the encoding map of the media type.
+// JSON media types are encoded with encoding/json, XML media types are
+// encoded with EncodeXML, and form media types are encoded with the
+// encoding objects for each property. For other
+// media types, the value must be []byte or string.
+// This function returns the content type of the encoded body, which
+// contains the boundary parameter for multipart.
+func (mediaType MediaType) Encode(root *Document, contentType string, value interface{}) (string, []byte, error) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", nil, ErrFormatInvalid{Target: "content type", Format: "media type"}
+	}
+	switch {
+	case mt == FormURLEncoded:
+		obj, err := formValues(value)
+		if err != nil {
+			return "", nil, err
+		}
+		b, err := mediaType.encodeURLEncoded(root, obj)
+		return contentType, b, err
+	case strings.HasPrefix(mt, "multipart/"):
+		obj, err := formValues(value)
+		if err != nil {
+			return "", nil, err
+		}
+		return mediaType.encodeMultipart(root, mt, obj)
+	case isJSONMediaType(mt):
+		b, err := json.Marshal(value)
+		return contentType, b, err
+	case isXMLMediaType(mt):
+		b, err := EncodeXML(root, mediaType.Schema, "", value)
+		return contentType, b, err
+	}
+	switch b := value.(type) {
+	case []byte:
+		return contentType, b, nil
+	case string:
+		return contentType, []byte(b), nil
+	}
+	return "", nil, ErrUnsupportedMediaType
+}
+
+// Decode given body of the content type, according to the schema and
+// the encoding map of the media type.
+// For form media types, the decoded value is map[string]interface{} which
+// values are coerced by the property schemas, and files in multipart body
+// are decoded as *FilePart. The other parts which have the headers in the
+// encoding of the property are decoded as *Part. XML media types are decoded with DecodeXML.
+// For other media types, the body is returned as is.
+func (mediaType MediaType) Decode(root *Document, contentType string, body []byte) (interface{}, error) {
+	mt, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrFormatInvalid{Target: "content type", Format: "media type"}
+	}
+	switch {
+	case mt == FormURLEncoded:
+		return mediaType.decodeURLEncoded(root, string(body))
+	case strings.HasPrefix(mt, "multipart/"):
+		boundary, ok := params["boundary"]
+		if !ok {
+			return nil, ErrRequired{Target: "multipart boundary"}
+		}
+		return mediaType.decodeMultipart(root, body, boundary)
+	case isJSONMediaType(mt):
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case isXMLMediaType(mt):
+		return DecodeXML(root, mediaType.Schema, body)
+	}
+	return body, nil
+}
+
+func isJSONMediaType(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// formValues converts given value into a map of properties.
+// Struct values are converted via encoding/json.
+func formValues(value interface{}) (map[string]interface{}, error) {
+	if obj, ok := value.(map[string]interface{}); ok {
+		return obj, nil
+	}
+	v, err := normalizeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, ErrValueInvalid{Reason: "must be object"}
+	}
+	return obj, nil
+}
+
+func (mediaType MediaType) schema(root *Document) (*Schema, error) {
+	return resolveSchema(root, mediaType.Schema)
+}
+
+func (mediaType MediaType) propertySchema(root *Document, schema *Schema, name string) (*Schema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	propSchema, ok := schema.Properties[name]
+	if !ok {
+		propSchema = schema.AdditionalProperties
+	}
+	return resolveSchema(root, propSchema)
+}
+
+// formCodec returns the style codec for the property in
+// application/x-www-form-urlencoded body.
+func (mediaType MediaType) formCodec(name string, schema *Schema) styleCodec {
+	var encoding Encoding
+	if e := mediaType.Encoding[name]; e != nil {
+		encoding = *e
+	}
+	return encoding.codec(name, schema)
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (mediaType MediaType) encodeURLEncoded(root *Document, obj map[string]interface{}) ([]byte, error) {
+	schema, err := mediaType.schema(root)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []string
+	for _, name := range sortedKeys(obj) {
+		propSchema, err := mediaType.propertySchema(root, schema, name)
+		if err != nil {
+			return nil, err
+		}
+		s, err := mediaType.formCodec(name, propSchema).serialize(obj[name])
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, s)
+	}
+	return []byte(strings.Join(pairs, "&")), nil
+}
+
+func (mediaType MediaType) decodeURLEncoded(root *Document, body string) (map[string]interface{}, error) {
+	schema, err := mediaType.schema(root)
+	if err != nil {
+		return nil, err
+	}
+	// "+" means a space in application/x-www-form-urlencoded
+	body = strings.Replace(body, "+", "%20", -1)
+
+	var names []string
+	seen := map[string]bool{}
+	if schema != nil {
+		for name := range schema.Properties {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	// the keys other than the properties are decoded if they are allowed
+	if schema == nil || len(schema.Properties) == 0 || schema.AdditionalProperties != nil {
+		for _, item := range strings.Split(body, "&") {
+			key := strings.SplitN(item, "=", 2)[0]
+			if key == "" || seen[key] || isPropertyKey(schema, key) {
+				continue
+			}
+			seen[key] = true
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+
+	ret := map[string]interface{}{}
+	for _, name := range names {
+		propSchema, err := mediaType.propertySchema(root, schema, name)
+		if err != nil {
+			return nil, err
+		}
+		v, err := mediaType.formCodec(name, propSchema).deserialize(body)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			ret[name] = v
+		}
+	}
+	return ret, nil
+}
+
+// isPropertyKey reports whether the key is a part of the property in
+// deepObject style, like address[city].
+func isPropertyKey(schema *Schema, key string) bool {
+	if schema == nil {
+		return false
+	}
+	if i := strings.IndexByte(key, '['); i > 0 {
+		_, ok := schema.Properties[key[:i]]
+		return ok
+	}
+	return false
+}
+
+func (mediaType MediaType) encodeMultipart(root *Document, mt string, obj map[string]interface{}) (string, []byte, error) {
+	schema, err := mediaType.schema(root)
+	if err != nil {
+		return "", nil, err
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, name := range sortedKeys(obj) {
+		propSchema, err := mediaType.propertySchema(root, schema, name)
+		if err != nil {
+			return "", nil, err
+		}
+		values := []interface{}{obj[name]}
+		if mediaType.isMultipartArray(name, obj[name], propSchema) {
+			values = nil
+			rv := reflect.ValueOf(obj[name])
+			for i := 0; i < rv.Len(); i++ {
+				values = append(values, rv.Index(i).Interface())
+			}
+		}
+		for _, v := range values {
+			if err := mediaType.writePart(root, w, name, v); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", nil, err
+	}
+	return mime.FormatMediaType(mt, map[string]string{"boundary": w.Boundary()}), buf.Bytes(), nil
+}
+
+// isMultipartArray reports whether the value is sent as multiple parts.
+// An array is sent as a part for each item unless the content type
+// of the property is JSON.
+func (mediaType MediaType) isMultipartArray(name string, v interface{}, schema *Schema) bool {
+	if _, ok := v.([]byte); ok {
+		return false
+	}
+	if encoding := mediaType.Encoding[name]; encoding != nil && encoding.ContentType != "" {
+		if mt, _, err := mime.ParseMediaType(encoding.ContentType); err == nil && isJSONMediaType(mt) {
+			return false
+		}
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	return schema == nil || schema.Type == "array"
+}
+
+func (mediaType MediaType) writePart(root *Document, w *multipart.Writer, name string, v interface{}) error {
+	encoding := mediaType.Encoding[name]
+	header := textproto.MIMEHeader{}
+	disposition := fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(name))
+
+	var contentType string
+	var content []byte
+	switch t := v.(type) {
+	case *FilePart:
+		if t == nil {
+			return nil
+		}
+		return mediaType.writePart(root, w, name, *t)
+	case *Part:
+		if t == nil {
+			return nil
+		}
+		return mediaType.writePart(root, w, name, *t)
+	case Part:
+		if err := encoding.setPartHeader(root, header, t.Header); err != nil {
+			return err
+		}
+		contentType, content, err := encodePartValue(encoding, t.Value)
+		if err != nil {
+			return err
+		}
+		return createPart(w, header, disposition, encoding, contentType, content)
+	case FilePart:
+		for k, values := range t.Header {
+			header[k] = values
+		}
+		if t.Filename != "" {
+			disposition += fmt.Sprintf(`; filename="%s"`, escapeQuotes(t.Filename))
+		}
+		contentType = t.ContentType
+		content = t.Content
+	case []byte:
+		content = t
+	default:
+		var err error
+		contentType, content, err = encodePartValue(encoding, v)
+		if err != nil {
+			return err
+		}
+	}
+	if err := encoding.setPartHeader(root, header, nil); err != nil {
+		return err
+	}
+	return createPart(w, header, disposition, encoding, contentType, content)
+}
+
+func createPart(w *multipart.Writer, header textproto.MIMEHeader, disposition string, encoding *Encoding, contentType string, content []byte) error {
+	if encoding != nil && encoding.ContentType != "" && contentType == "" {
+		contentType = firstContentType(encoding.ContentType)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Disposition", disposition)
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(content)
+	return err
+}
+
+// partHeaders returns the header objects in the encoding, resolving their
+// references. Content-Type is described by encoding.contentType, so its
+// header object is ignored.
+func (encoding *Encoding) partHeaders(root *Document) (map[string]*Header, error) {
+	ret := map[string]*Header{}
+	if encoding == nil {
+		return ret, nil
+	}
+	for name, header := range encoding.Headers {
+		key := textproto.CanonicalMIMEHeaderKey(name)
+		if key == "Content-Type" || header == nil {
+			continue
+		}
+		if header.Ref != "" {
+			var err error
+			if header, err = ResolveHeader(root, header.Ref); err != nil {
+				return nil, err
+			}
+		}
+		ret[key] = header
+	}
+	return ret, nil
+}
+
+// setPartHeader serializes the values of the part headers with the header
+// objects in the encoding and sets them to the header of the part.
+func (encoding *Encoding) setPartHeader(root *Document, header textproto.MIMEHeader, values map[string]interface{}) error {
+	headers, err := encoding.partHeaders(root)
+	if err != nil {
+		return err
+	}
+	given := map[string]interface{}{}
+	for name, v := range values {
+		given[textproto.CanonicalMIMEHeaderKey(name)] = v
+	}
+	keys := make([]string, 0, len(given))
+	for key := range given {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		h, ok := headers[key]
+		if !ok {
+			h = &Header{}
+		}
+		s, err := h.Serialize(given[key])
+		if err != nil {
+			return err
+		}
+		header.Set(key, s)
+	}
+	for key, h := range headers {
+		if _, ok := header[key]; !ok && h.Required {
+			return ErrRequired{Target: "header " + key}
+		}
+	}
+	return nil
+}
+
+// encodePartValue encodes a non-file value of the multipart body.
+// Objects and arrays are encoded as application/json, and primitives are
+// encoded as text/plain unless encoding.contentType is specified.
+func encodePartValue(encoding *Encoding, v interface{}) (string, []byte, error) {
+	contentType := "text/plain"
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		contentType = "application/json"
+	}
+	if encoding != nil && encoding.ContentType != "" {
+		contentType = firstContentType(encoding.ContentType)
+	}
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && isJSONMediaType(mt) {
+		b, err := json.Marshal(v)
+		return contentType, b, err
+	}
+	return contentType, []byte(stringifyValue(v)), nil
+}
+
+// firstContentType returns the first one of comma-separated content types
+// in encoding.contentType.
+func firstContentType(contentTypes string) string {
+	return strings.TrimSpace(strings.Split(contentTypes, ",")[0])
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+func (mediaType MediaType) decodeMultipart(root *Document, body []byte, boundary string) (map[string]interface{}, error) {
+	schema, err := mediaType.schema(root)
+	if err != nil {
+		return nil, err
+	}
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	ret := map[string]interface{}{}
+	for {
+		part, err := r.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		name := part.FormName()
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		propSchema, err := mediaType.propertySchema(root, schema, name)
+		if err != nil {
+			return nil, err
+		}
+		itemSchema := propSchema
+		isArray := propSchema != nil && propSchema.Type == "array"
+		if isArray {
+			itemSchema, err = resolveSchema(root, propSchema.Items)
+			if err != nil {
+				return nil, err
+			}
+		}
+		v, err := decodePart(part, content, itemSchema)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := v.(*FilePart); !ok {
+			if v, err = mediaType.Encoding[name].decodePartHeader(root, part.Header, v); err != nil {
+				return nil, err
+			}
+		}
+		if !isArray {
+			ret[name] = v
+			continue
+		}
+		if arr, ok := v.([]interface{}); ok {
+			// whole array is sent as a JSON part
+			ret[name] = arr
+			continue
+		}
+		items, _ := ret[name].([]interface{})
+		ret[name] = append(items, v)
+	}
+	return ret, nil
+}
+
+// decodePartHeader returns *Part with the values of the headers in the
+// encoding if the part has some of them, or v as it is.
+func (encoding *Encoding) decodePartHeader(root *Document, header textproto.MIMEHeader, v interface{}) (interface{}, error) {
+	headers, err := encoding.partHeaders(root)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	for key, h := range headers {
+		s, ok := header[key]
+		if !ok {
+			if h.Required {
+				return nil, ErrRequired{Target: "header " + key}
+			}
+			continue
+		}
+		hv, err := h.Deserialize(strings.Join(s, ","))
+		if err != nil {
+			return nil, err
+		}
+		values[key] = hv
+	}
+	if len(values) == 0 {
+		return v, nil
+	}
+	return &Part{Value: v, Header: values}, nil
+}
+
+func decodePart(part *multipart.Part, content []byte, schema *Schema) (interface{}, error) {
+	contentType := part.Header.Get("Content-Type")
+	mt, _, _ := mime.ParseMediaType(contentType)
+	isBinary := schema != nil && schema.Type == "string" && (schema.Format == "binary" || schema.Format == "base64")
+	if part.FileName() != "" || isBinary {
+		return &FilePart{
+			Filename:    part.FileName(),
+			ContentType: contentType,
+			Header:      part.Header,
+			Content:     content,
+		}, nil
+	}
+	if isJSONMediaType(mt) {
+		var v interface{}
+		if err := json.Unmarshal(content, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	return coercePrimitive(string(content), schema, part.FormName())
+}
@@ -0,0 +1,233 @@
+package openapi_test
+
+import (
+	"mime"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestMediaType_URLEncoded(t *testing.T) {
+	mediaType := openapi.MediaType{
+		Schema: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id":      &openapi.Schema{Type: "integer"},
+				"name":    &openapi.Schema{Type: "string"},
+				"tags":    &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+				"ids":     &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "integer"}},
+				"address": &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"city": &openapi.Schema{Type: "string"}}},
+			},
+		},
+		Encoding: map[string]*openapi.Encoding{
+			"ids":     &openapi.Encoding{Style: "pipeDelimited"},
+			"address": &openapi.Encoding{Style: "deepObject", Explode: boolPtr(true)},
+		},
+	}
+	value := map[string]interface{}{
+		"id":      int64(1),
+		"name":    "foo bar",
+		"tags":    []interface{}{"a", "b"},
+		"ids":     []interface{}{int64(1), int64(2)},
+		"address": map[string]interface{}{"city": "Tokyo"},
+	}
+	contentType, body, err := mediaType.Encode(nil, openapi.FormURLEncoded, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != openapi.FormURLEncoded {
+		t.Errorf("unexpected content type: %s", contentType)
+	}
+	expect := "address%5Bcity%5D=Tokyo&id=1&ids=1|2&name=foo%20bar&tags=a&tags=b"
+	if string(body) != expect {
+		t.Errorf("%s != %s", body, expect)
+	}
+	decoded, err := mediaType.Decode(nil, contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("%#v != %#v", decoded, value)
+	}
+
+	decoded, err = openapi.MediaType{}.Decode(nil, openapi.FormURLEncoded, []byte("a=1+2&b=c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := map[string]interface{}{"a": "1 2", "b": "c"}; !reflect.DeepEqual(decoded, expect) {
+		t.Errorf("%#v != %#v", decoded, expect)
+	}
+}
+
+func TestMediaType_Multipart(t *testing.T) {
+	mediaType := openapi.MediaType{
+		Schema: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id":      &openapi.Schema{Type: "integer"},
+				"address": &openapi.Schema{Type: "object"},
+				"tags":    &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+				"profileImage": &openapi.Schema{
+					Type:   "string",
+					Format: "binary",
+				},
+				"attachments": &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string", Format: "binary"}},
+			},
+		},
+		Encoding: map[string]*openapi.Encoding{
+			"profileImage": &openapi.Encoding{
+				ContentType: "image/png, image/jpeg",
+				Headers: map[string]*openapi.Header{
+					"X-Rate-Limit-Limit": &openapi.Header{Schema: &openapi.Schema{Type: "integer"}},
+				},
+			},
+		},
+	}
+	value := map[string]interface{}{
+		"id":      1,
+		"address": map[string]interface{}{"city": "Tokyo"},
+		"tags":    []string{"a", "b"},
+		"profileImage": &openapi.FilePart{
+			Header:  textproto.MIMEHeader{"X-Rate-Limit-Limit": {"100"}},
+			Content: []byte("png"),
+		},
+		"cover": (*openapi.FilePart)(nil),
+		"attachments": []interface{}{
+			&openapi.FilePart{Filename: "a.txt", ContentType: "text/plain", Content: []byte("a")},
+			openapi.FilePart{Filename: "b.txt", Content: []byte("b")},
+		},
+	}
+	contentType, body, err := mediaType.Encode(nil, openapi.MultipartFormData, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mt != openapi.MultipartFormData || params["boundary"] == "" {
+		t.Fatalf("unexpected content type: %s", contentType)
+	}
+	for _, s := range []string{
+		`Content-Disposition: form-data; name="profileImage"`,
+		"X-Rate-Limit-Limit: 100",
+		`Content-Disposition: form-data; name="attachments"; filename="a.txt"`,
+	} {
+		if !strings.Contains(string(body), s) {
+			t.Errorf("body should contain %s:\n%s", s, body)
+		}
+	}
+	if strings.Contains(string(body), `name="cover"`) {
+		t.Errorf("nil file should not be sent:\n%s", body)
+	}
+
+	decoded, err := mediaType.Decode(nil, contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := decoded.(map[string]interface{})
+	if obj["id"] != int64(1) {
+		t.Errorf("unexpected id: %#v", obj["id"])
+	}
+	if expect := map[string]interface{}{"city": "Tokyo"}; !reflect.DeepEqual(obj["address"], expect) {
+		t.Errorf("%#v != %#v", obj["address"], expect)
+	}
+	if expect := []interface{}{"a", "b"}; !reflect.DeepEqual(obj["tags"], expect) {
+		t.Errorf("%#v != %#v", obj["tags"], expect)
+	}
+	image, ok := obj["profileImage"].(*openapi.FilePart)
+	if !ok || string(image.Content) != "png" || image.ContentType != "image/png" {
+		t.Errorf("unexpected profileImage: %#v", obj["profileImage"])
+	}
+	attachments, ok := obj["attachments"].([]interface{})
+	if !ok || len(attachments) != 2 {
+		t.Fatalf("unexpected attachments: %#v", obj["attachments"])
+	}
+	if file := attachments[1].(*openapi.FilePart); file.Filename != "b.txt" || string(file.Content) != "b" || file.ContentType != "application/octet-stream" {
+		t.Errorf("unexpected attachment: %#v", file)
+	}
+}
+
+func TestMediaType_EncodeError(t *testing.T) {
+	if _, _, err := (openapi.MediaType{}).Encode(nil, "image/png", 1); err != openapi.ErrUnsupportedMediaType {
+		t.Errorf("error should be %v, but %v", openapi.ErrUnsupportedMediaType, err)
+	}
+	if _, _, err := (openapi.MediaType{}).Encode(nil, openapi.FormURLEncoded, 1); err == nil {
+		t.Error("error should occur")
+	}
+	if _, err := (openapi.MediaType{}).Decode(nil, openapi.MultipartFormData, nil); err != (openapi.ErrRequired{Target: "multipart boundary"}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMediaType_URLEncoded_AdditionalProperties(t *testing.T) {
+	mediaType := openapi.MediaType{
+		Schema: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id": &openapi.Schema{Type: "integer"},
+			},
+			AdditionalProperties: &openapi.Schema{Type: "integer"},
+		},
+	}
+	decoded, err := mediaType.Decode(nil, openapi.FormURLEncoded, []byte("id=1&count=2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := map[string]interface{}{"id": int64(1), "count": int64(2)}; !reflect.DeepEqual(decoded, expect) {
+		t.Errorf("%#v != %#v", decoded, expect)
+	}
+
+	mediaType.Schema.AdditionalProperties = nil
+	decoded, err = mediaType.Decode(nil, openapi.FormURLEncoded, []byte("id=1&count=2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := map[string]interface{}{"id": int64(1)}; !reflect.DeepEqual(decoded, expect) {
+		t.Errorf("%#v != %#v", decoded, expect)
+	}
+}
+
+func TestMediaType_Multipart_PartHeader(t *testing.T) {
+	mediaType := openapi.MediaType{
+		Schema: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id": &openapi.Schema{Type: "integer"},
+			},
+		},
+		Encoding: map[string]*openapi.Encoding{
+			"id": &openapi.Encoding{
+				Headers: map[string]*openapi.Header{
+					"X-Tags": &openapi.Header{Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+	value := map[string]interface{}{
+		"id": &openapi.Part{
+			Value:  int64(1),
+			Header: map[string]interface{}{"X-Tags": []interface{}{"a", "b"}},
+		},
+	}
+	contentType, body, err := mediaType.Encode(nil, openapi.MultipartFormData, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "X-Tags: a,b") {
+		t.Errorf("body should contain the part header:\n%s", body)
+	}
+	decoded, err := mediaType.Decode(nil, contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("%#v != %#v", decoded, value)
+	}
+
+	mediaType.Encoding["id"].Headers["X-Tags"].Required = true
+	value["id"] = int64(1)
+	if _, _, err := mediaType.Encode(nil, openapi.MultipartFormData, value); err != (openapi.ErrRequired{Target: "header X-Tags"}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
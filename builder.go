@@ -0,0 +1,569 @@
+package openapi
+
+import "strings"
+
+// DocumentBuilder builds a Document programmatically.
+//
+//	doc, err := openapi.NewDocument("Petstore", "1.0.0").
+//		Server("https://petstore.example.com/v1", "").
+//		Path("/pets").Get(openapi.NewOperation("listPets").
+//		Returns("200", "pets", openapi.Array(pet))).
+//		Build()
+type DocumentBuilder struct {
+	doc *Document
+}
+
+// NewDocument returns a builder of an OpenAPI 3.0.2 document with given
+// title and version of the API.
+func NewDocument(title, version string) *DocumentBuilder {
+	return &DocumentBuilder{
+		doc: &Document{
+			Version: "3.0.2",
+			Info: &Info{
+				Title:   title,
+				Version: version,
+			},
+			Paths: Paths{},
+		},
+	}
+}
+
+// Description sets info.description.
+func (b *DocumentBuilder) Description(description string) *DocumentBuilder {
+	b.doc.Info.Description = description
+	return b
+}
+
+// Server appends a server.
+func (b *DocumentBuilder) Server(url, description string) *DocumentBuilder {
+	b.doc.Servers = append(b.doc.Servers, &Server{URL: url, Description: description})
+	return b
+}
+
+// Tag appends a tag.
+func (b *DocumentBuilder) Tag(name, description string) *DocumentBuilder {
+	b.doc.Tags = append(b.doc.Tags, &Tag{Name: name, Description: description})
+	return b
+}
+
+// Security appends a security requirement applied to all the operations.
+func (b *DocumentBuilder) Security(name string, scopes ...string) *DocumentBuilder {
+	b.doc.Security = append(b.doc.Security, newSecurityRequirement(name, scopes))
+	return b
+}
+
+// SecurityScheme registers a security scheme in components.
+func (b *DocumentBuilder) SecurityScheme(name string, securityScheme *SecurityScheme) *DocumentBuilder {
+	components := b.components()
+	if components.SecuritySchemes == nil {
+		components.SecuritySchemes = map[string]*SecurityScheme{}
+	}
+	components.SecuritySchemes[name] = securityScheme
+	return b
+}
+
+// Schema registers a schema in components and returns a reference to it.
+func (b *DocumentBuilder) Schema(name string, schema *SchemaBuilder) *SchemaBuilder {
+	components := b.components()
+	if components.Schemas == nil {
+		components.Schemas = map[string]*Schema{}
+	}
+	components.Schemas[name] = schema.Build()
+	return Ref("#/components/schemas/" + name)
+}
+
+// Parameter registers a parameter in components and returns a reference to it.
+func (b *DocumentBuilder) Parameter(name string, parameter *Parameter) *Parameter {
+	components := b.components()
+	if components.Parameters == nil {
+		components.Parameters = map[string]*Parameter{}
+	}
+	components.Parameters[name] = parameter
+	return &Parameter{Ref: "#/components/parameters/" + name}
+}
+
+// Response registers a response in components and returns a reference to it.
+func (b *DocumentBuilder) Response(name string, response *Response) *Response {
+	components := b.components()
+	if components.Responses == nil {
+		components.Responses = map[string]*Response{}
+	}
+	components.Responses[name] = response
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+// RequestBody registers a request body in components and returns a reference to it.
+func (b *DocumentBuilder) RequestBody(name string, requestBody *RequestBody) *RequestBody {
+	components := b.components()
+	if components.RequestBodies == nil {
+		components.RequestBodies = map[string]*RequestBody{}
+	}
+	components.RequestBodies[name] = requestBody
+	return &RequestBody{Ref: "#/components/requestBodies/" + name}
+}
+
+func (b *DocumentBuilder) components() *Components {
+	if b.doc.Components == nil {
+		b.doc.Components = &Components{}
+	}
+	return b.doc.Components
+}
+
+// Path returns a builder of the path item for given path.
+// The path item is created if it does not exist.
+func (b *DocumentBuilder) Path(path string) *PathBuilder {
+	pathItem, ok := b.doc.Paths[path]
+	if !ok || pathItem == nil {
+		pathItem = &PathItem{}
+		b.doc.Paths[path] = pathItem
+	}
+	return &PathBuilder{parent: b, pathItem: pathItem}
+}
+
+// Build validates and returns the document.
+func (b *DocumentBuilder) Build() (*Document, error) {
+	b.doc.linkSecurityRequirements()
+	if err := b.doc.Validate(); err != nil {
+		return nil, err
+	}
+	return b.doc, nil
+}
+
+// PathBuilder builds a path item in a document.
+type PathBuilder struct {
+	parent   *DocumentBuilder
+	pathItem *PathItem
+}
+
+// Summary sets the summary of the path item.
+func (pb *PathBuilder) Summary(summary string) *PathBuilder {
+	pb.pathItem.Summary = summary
+	return pb
+}
+
+// Parameter appends a parameter shared by all the operations of the path item.
+func (pb *PathBuilder) Parameter(parameter *Parameter) *PathBuilder {
+	pb.pathItem.Parameters = append(pb.pathItem.Parameters, parameter)
+	return pb
+}
+
+// Get sets the GET operation.
+func (pb *PathBuilder) Get(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Get = op.Build()
+	return pb
+}
+
+// Put sets the PUT operation.
+func (pb *PathBuilder) Put(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Put = op.Build()
+	return pb
+}
+
+// Post sets the POST operation.
+func (pb *PathBuilder) Post(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Post = op.Build()
+	return pb
+}
+
+// Delete sets the DELETE operation.
+func (pb *PathBuilder) Delete(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Delete = op.Build()
+	return pb
+}
+
+// Options sets the OPTIONS operation.
+func (pb *PathBuilder) Options(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Options = op.Build()
+	return pb
+}
+
+// Head sets the HEAD operation.
+func (pb *PathBuilder) Head(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Head = op.Build()
+	return pb
+}
+
+// Patch sets the PATCH operation.
+func (pb *PathBuilder) Patch(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Patch = op.Build()
+	return pb
+}
+
+// Trace sets the TRACE operation.
+func (pb *PathBuilder) Trace(op *OperationBuilder) *PathBuilder {
+	pb.pathItem.Trace = op.Build()
+	return pb
+}
+
+// Path returns a builder of another path item in the same document.
+func (pb *PathBuilder) Path(path string) *PathBuilder {
+	return pb.parent.Path(path)
+}
+
+// Document returns the builder of the document.
+func (pb *PathBuilder) Document() *DocumentBuilder {
+	return pb.parent
+}
+
+// Build validates and returns the document.
+func (pb *PathBuilder) Build() (*Document, error) {
+	return pb.parent.Build()
+}
+
+// OperationBuilder builds an operation.
+type OperationBuilder struct {
+	op *Operation
+}
+
+// NewOperation returns a builder of an operation with given operationId.
+func NewOperation(operationID string) *OperationBuilder {
+	return &OperationBuilder{op: &Operation{OperationID: operationID}}
+}
+
+// Summary sets the summary.
+func (ob *OperationBuilder) Summary(summary string) *OperationBuilder {
+	ob.op.Summary = summary
+	return ob
+}
+
+// Description sets the description.
+func (ob *OperationBuilder) Description(description string) *OperationBuilder {
+	ob.op.Description = description
+	return ob
+}
+
+// Tags appends the tags.
+func (ob *OperationBuilder) Tags(tags ...string) *OperationBuilder {
+	ob.op.Tags = append(ob.op.Tags, tags...)
+	return ob
+}
+
+// Deprecated marks the operation as deprecated.
+func (ob *OperationBuilder) Deprecated() *OperationBuilder {
+	ob.op.Deprecated = true
+	return ob
+}
+
+// Parameter appends a parameter.
+func (ob *OperationBuilder) Parameter(parameter *Parameter) *OperationBuilder {
+	ob.op.Parameters = append(ob.op.Parameters, parameter)
+	return ob
+}
+
+// RequestBody sets the request body.
+func (ob *OperationBuilder) RequestBody(requestBody *RequestBody) *OperationBuilder {
+	ob.op.RequestBody = requestBody
+	return ob
+}
+
+// Accepts sets a required request body which has given content type and schema.
+func (ob *OperationBuilder) Accepts(contentType string, schema *SchemaBuilder) *OperationBuilder {
+	ob.op.RequestBody = &RequestBody{
+		Content: map[string]*MediaType{
+			contentType: &MediaType{Schema: schema.Build()},
+		},
+		Required: true,
+	}
+	return ob
+}
+
+// Response sets the response for given status code.
+func (ob *OperationBuilder) Response(status string, response *Response) *OperationBuilder {
+	if ob.op.Responses == nil {
+		ob.op.Responses = Responses{}
+	}
+	ob.op.Responses[status] = response
+	return ob
+}
+
+// Returns sets the response for given status code. If schema is not nil,
+// the response has application/json content of the schema.
+func (ob *OperationBuilder) Returns(status, description string, schema *SchemaBuilder) *OperationBuilder {
+	response := &Response{Description: description}
+	if schema != nil {
+		response.Content = map[string]*MediaType{
+			"application/json": &MediaType{Schema: schema.Build()},
+		}
+	}
+	return ob.Response(status, response)
+}
+
+// Callback sets a callback.
+func (ob *OperationBuilder) Callback(name string, callback *Callback) *OperationBuilder {
+	if ob.op.Callbacks == nil {
+		ob.op.Callbacks = map[string]*Callback{}
+	}
+	ob.op.Callbacks[name] = callback
+	return ob
+}
+
+// Security appends a security requirement.
+func (ob *OperationBuilder) Security(name string, scopes ...string) *OperationBuilder {
+	security := append(ob.op.securityRequirements(), newSecurityRequirement(name, scopes))
+	ob.op.Security = &security
+	return ob
+}
+
+// Server appends a server which overrides the servers of the document.
+func (ob *OperationBuilder) Server(url, description string) *OperationBuilder {
+	ob.op.Servers = append(ob.op.Servers, &Server{URL: url, Description: description})
+	return ob
+}
+
+// Build returns the operation.
+func (ob *OperationBuilder) Build() *Operation {
+	if ob == nil {
+		return nil
+	}
+	return ob.op
+}
+
+func newSecurityRequirement(name string, scopes []string) *SecurityRequirement {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &SecurityRequirement{mp: map[string][]string{name: scopes}}
+}
+
+// PathParam returns a required path parameter.
+func PathParam(name string, schema *SchemaBuilder) *Parameter {
+	return &Parameter{Name: name, In: InPath, Required: true, Schema: schema.Build()}
+}
+
+// QueryParam returns an optional query parameter.
+func QueryParam(name string, schema *SchemaBuilder) *Parameter {
+	return &Parameter{Name: name, In: InQuery, Schema: schema.Build()}
+}
+
+// HeaderParam returns an optional header parameter.
+func HeaderParam(name string, schema *SchemaBuilder) *Parameter {
+	return &Parameter{Name: name, In: InHeader, Schema: schema.Build()}
+}
+
+// CookieParam returns an optional cookie parameter.
+func CookieParam(name string, schema *SchemaBuilder) *Parameter {
+	return &Parameter{Name: name, In: InCookie, Schema: schema.Build()}
+}
+
+// SchemaBuilder builds a schema.
+type SchemaBuilder struct {
+	schema *Schema
+}
+
+func newSchemaBuilder(typ string) *SchemaBuilder {
+	return &SchemaBuilder{schema: &Schema{Type: typ}}
+}
+
+// String returns a builder of string schema.
+func String() *SchemaBuilder { return newSchemaBuilder("string") }
+
+// Integer returns a builder of integer schema.
+func Integer() *SchemaBuilder { return newSchemaBuilder("integer") }
+
+// Number returns a builder of number schema.
+func Number() *SchemaBuilder { return newSchemaBuilder("number") }
+
+// Boolean returns a builder of boolean schema.
+func Boolean() *SchemaBuilder { return newSchemaBuilder("boolean") }
+
+// Object returns a builder of object schema.
+func Object() *SchemaBuilder { return newSchemaBuilder("object") }
+
+// Array returns a builder of array schema whose items are given schema.
+func Array(items *SchemaBuilder) *SchemaBuilder {
+	sb := newSchemaBuilder("array")
+	sb.schema.Items = items.Build()
+	return sb
+}
+
+// Ref returns a builder of reference schema.
+func Ref(ref string) *SchemaBuilder {
+	return &SchemaBuilder{schema: &Schema{Ref: ref}}
+}
+
+// AllOf returns a builder of schema which must be valid against all of the schemas.
+func AllOf(schemas ...*SchemaBuilder) *SchemaBuilder {
+	return &SchemaBuilder{schema: &Schema{AllOf: buildSchemas(schemas)}}
+}
+
+// OneOf returns a builder of schema which must be valid against exactly one of the schemas.
+func OneOf(schemas ...*SchemaBuilder) *SchemaBuilder {
+	return &SchemaBuilder{schema: &Schema{OneOf: buildSchemas(schemas)}}
+}
+
+// AnyOf returns a builder of schema which must be valid against any of the schemas.
+func AnyOf(schemas ...*SchemaBuilder) *SchemaBuilder {
+	return &SchemaBuilder{schema: &Schema{AnyOf: buildSchemas(schemas)}}
+}
+
+func buildSchemas(builders []*SchemaBuilder) []*Schema {
+	schemas := make([]*Schema, 0, len(builders))
+	for _, sb := range builders {
+		schemas = append(schemas, sb.Build())
+	}
+	return schemas
+}
+
+// Title sets the title.
+func (sb *SchemaBuilder) Title(title string) *SchemaBuilder {
+	sb.schema.Title = title
+	return sb
+}
+
+// Description sets the description.
+func (sb *SchemaBuilder) Description(description string) *SchemaBuilder {
+	sb.schema.Description = description
+	return sb
+}
+
+// Format sets the format.
+func (sb *SchemaBuilder) Format(format string) *SchemaBuilder {
+	sb.schema.Format = format
+	return sb
+}
+
+// Pattern sets the pattern.
+func (sb *SchemaBuilder) Pattern(pattern string) *SchemaBuilder {
+	sb.schema.Pattern = pattern
+	return sb
+}
+
+// Enum appends the enum values.
+func (sb *SchemaBuilder) Enum(values ...interface{}) *SchemaBuilder {
+	sb.schema.Enum = append(sb.schema.Enum, values...)
+	return sb
+}
+
+// Default sets the default value.
+func (sb *SchemaBuilder) Default(value interface{}) *SchemaBuilder {
+	sb.schema.Default = value
+	return sb
+}
+
+// Example sets the example.
+func (sb *SchemaBuilder) Example(example interface{}) *SchemaBuilder {
+	sb.schema.Example = example
+	return sb
+}
+
+// Minimum sets the minimum. If exclusive is true, the value must be
+// greater than the minimum.
+func (sb *SchemaBuilder) Minimum(minimum float64, exclusive bool) *SchemaBuilder {
+	sb.schema.Minimum = NewDecimal(minimum)
+	sb.schema.ExclusiveMinimum = exclusive
+	return sb
+}
+
+// Maximum sets the maximum. If exclusive is true, the value must be
+// less than the maximum.
+func (sb *SchemaBuilder) Maximum(maximum float64, exclusive bool) *SchemaBuilder {
+	sb.schema.Maximum = NewDecimal(maximum)
+	sb.schema.ExclusiveMaximum = exclusive
+	return sb
+}
+
+// MultipleOf sets the multipleOf.
+func (sb *SchemaBuilder) MultipleOf(multipleOf float64) *SchemaBuilder {
+	sb.schema.MultipleOf = NewDecimal(multipleOf)
+	return sb
+}
+
+// MinLength sets the minLength.
+func (sb *SchemaBuilder) MinLength(minLength int) *SchemaBuilder {
+	sb.schema.MinLength = minLength
+	return sb
+}
+
+// MaxLength sets the maxLength.
+func (sb *SchemaBuilder) MaxLength(maxLength int) *SchemaBuilder {
+	sb.schema.MaxLength = &maxLength
+	return sb
+}
+
+// MinItems sets the minItems.
+func (sb *SchemaBuilder) MinItems(minItems int) *SchemaBuilder {
+	sb.schema.MinItems = minItems
+	return sb
+}
+
+// MaxItems sets the maxItems.
+func (sb *SchemaBuilder) MaxItems(maxItems int) *SchemaBuilder {
+	sb.schema.MaxItems = &maxItems
+	return sb
+}
+
+// Nullable marks the schema as nullable.
+func (sb *SchemaBuilder) Nullable() *SchemaBuilder {
+	sb.schema.Nullable = true
+	return sb
+}
+
+// ReadOnly marks the schema as read only.
+func (sb *SchemaBuilder) ReadOnly() *SchemaBuilder {
+	sb.schema.ReadOnly = true
+	return sb
+}
+
+// WriteOnly marks the schema as write only.
+func (sb *SchemaBuilder) WriteOnly() *SchemaBuilder {
+	sb.schema.WriteOnly = true
+	return sb
+}
+
+// Deprecated marks the schema as deprecated.
+func (sb *SchemaBuilder) Deprecated() *SchemaBuilder {
+	sb.schema.Deprecated = true
+	return sb
+}
+
+// Property sets a property of the object.
+func (sb *SchemaBuilder) Property(name string, schema *SchemaBuilder) *SchemaBuilder {
+	if sb.schema.Properties == nil {
+		sb.schema.Properties = map[string]*Schema{}
+	}
+	sb.schema.Properties[name] = schema.Build()
+	return sb
+}
+
+// Required appends the names of required properties.
+func (sb *SchemaBuilder) Required(names ...string) *SchemaBuilder {
+	sb.schema.Required = append(sb.schema.Required, names...)
+	return sb
+}
+
+// AdditionalProperties sets the schema of additional properties.
+func (sb *SchemaBuilder) AdditionalProperties(schema *SchemaBuilder) *SchemaBuilder {
+	sb.schema.AdditionalProperties = schema.Build()
+	return sb
+}
+
+// Discriminator sets the discriminator. The mapping values which are
+// component names are converted to the references.
+func (sb *SchemaBuilder) Discriminator(propertyName string, mapping map[string]string) *SchemaBuilder {
+	var m map[string]string
+	if mapping != nil {
+		m = make(map[string]string, len(mapping))
+		for k, v := range mapping {
+			if !strings.Contains(v, "/") {
+				v = "#/components/schemas/" + v
+			}
+			m[k] = v
+		}
+	}
+	sb.schema.Discriminator = &Discriminator{PropertyName: propertyName, Mapping: m}
+	return sb
+}
+
+// XML sets the XML object.
+func (sb *SchemaBuilder) XML(x *XML) *SchemaBuilder {
+	sb.schema.XML = x
+	return sb
+}
+
+// Build returns the schema.
+func (sb *SchemaBuilder) Build() *Schema {
+	if sb == nil {
+		return nil
+	}
+	return sb.schema
+}
@@ -0,0 +1,121 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func buildPetstore(t *testing.T) *openapi.Document {
+	b := openapi.NewDocument("Petstore", "1.0.0").
+		Description("sample").
+		Server("https://petstore.example.com/v1", "production").
+		Tag("pets", "").
+		SecurityScheme("api_key", &openapi.SecurityScheme{Type: "apiKey", Name: "X-API-KEY", In: openapi.InHeader}).
+		Security("api_key")
+	pet := b.Schema("Pet", openapi.Object().
+		Property("id", openapi.String().Format("uuid").ReadOnly()).
+		Property("name", openapi.String().MinLength(1)).
+		Property("tag", openapi.String().Nullable()).
+		Required("id", "name"))
+	notFound := b.Response("NotFound", &openapi.Response{Description: "not found"})
+
+	doc, err := b.
+		Path("/pets").
+		Get(openapi.NewOperation("listPets").
+			Tags("pets").
+			Parameter(openapi.QueryParam("limit", openapi.Integer().Format("int32").Maximum(100, false))).
+			Returns("200", "pets", openapi.Array(pet))).
+		Post(openapi.NewOperation("createPet").
+			Tags("pets").
+			Accepts("application/json", pet).
+			Returns("201", "created", nil)).
+		Path("/pets/{petId}").
+		Parameter(openapi.PathParam("petId", openapi.String().Format("uuid"))).
+		Get(openapi.NewOperation("showPetById").
+			Returns("200", "a pet", pet).
+			Response("404", notFound)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestDocumentBuilder(t *testing.T) {
+	doc := buildPetstore(t)
+
+	op := doc.Paths["/pets"].Get
+	if op == nil || op.OperationID != "listPets" {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+	schema := op.Responses["200"].Content["application/json"].Schema
+	if schema.Type != "array" || schema.Items.Ref != "#/components/schemas/Pet" {
+		t.Errorf("unexpected schema: %+v", schema)
+	}
+	pet, err := openapi.ResolveSchema(doc, schema.Items.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pet.Properties["id"].Format != "uuid" {
+		t.Errorf("unexpected pet schema: %+v", pet)
+	}
+	if got := doc.Security[0].Names(); !reflect.DeepEqual(got, []string{"api_key"}) {
+		t.Errorf("%v != %v", got, []string{"api_key"})
+	}
+}
+
+func TestDocumentBuilder_Invalid(t *testing.T) {
+	_, err := openapi.NewDocument("", "1.0.0").Build()
+	if err != (openapi.ErrRequired{Target: "info.title"}) {
+		t.Errorf("%v != %v", err, openapi.ErrRequired{Target: "info.title"})
+	}
+	_, err = openapi.NewDocument("foo", "1.0.0").
+		Path("/pets").
+		Get(openapi.NewOperation("listPets").Security("unknown")).
+		Build()
+	if err == nil {
+		t.Error("error should be occurred")
+	}
+}
+
+func TestDocumentBuilder_Serialize(t *testing.T) {
+	doc := buildPetstore(t)
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := openapi.Load(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Paths, doc.Paths) {
+		t.Errorf("paths differ after YAML round trip:\n%s", b)
+	}
+
+	j, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(j, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["openapi"] != "3.0.2" {
+		t.Errorf("%v != 3.0.2", v["openapi"])
+	}
+	fromJSON, err := openapi.Load(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fromJSON.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
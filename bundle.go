@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// BundleFile loads the spec file and the files referred by its external
+// references like "schemas/pet.yaml" or "common.yaml#/components/schemas/Pet",
+// and returns a single document.
+// The objects referred by the external references are added into the
+// components of the document, named after the last part of the reference
+// or the file name, and the references are replaced by the references to
+// the components. The path items, which cannot be components, are inlined.
+func BundleFile(filename string) (*Document, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	b := &bundler{rootFile: abs, files: map[string]interface{}{}, refs: map[string]string{}}
+	root, err := b.load(abs)
+	if err != nil {
+		return nil, err
+	}
+	b.root, _ = root.(map[string]interface{})
+	if b.root == nil {
+		return nil, ErrFormatInvalid{Target: "document " + filename}
+	}
+	for _, k := range sortedKeys(b.root) {
+		bundled, err := b.bundle(abs, b.root[k], bundleChildKind("", k))
+		if err != nil {
+			return nil, err
+		}
+		b.root[k] = bundled
+	}
+	out, err := yaml.Marshal(b.root)
+	if err != nil {
+		return nil, err
+	}
+	return Load(out)
+}
+
+type bundler struct {
+	rootFile string
+	root     map[string]interface{}
+	// files are the loaded files keyed by the absolute path.
+	files map[string]interface{}
+	// refs are the local references of the bundled external references,
+	// keyed by the absolute path of the file and the fragment.
+	refs map[string]string
+}
+
+func (b *bundler) load(filename string) (interface{}, error) {
+	if v, ok := b.files[filename]; ok {
+		return v, nil
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v = yamlToJSONValue(v)
+	b.files[filename] = v
+	return v, nil
+}
+
+// bundle replaces the external references in the value loaded from the
+// file. The kind is the kind of the component which the value can be.
+func (b *bundler) bundle(file string, v interface{}, kind string) (interface{}, error) {
+	if kind == bundleNoReference {
+		return v, nil
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := c["$ref"].(string); ok {
+			return b.bundleReference(file, ref, strings.TrimSuffix(kind, "*"))
+		}
+		// in order of the keys, so that the names of the components are stable
+		for _, k := range sortedKeys(c) {
+			bundled, err := b.bundle(file, c[k], bundleChildKind(kind, k))
+			if err != nil {
+				return nil, err
+			}
+			c[k] = bundled
+		}
+	case []interface{}:
+		for i, val := range c {
+			bundled, err := b.bundle(file, val, bundleChildKind(kind, ""))
+			if err != nil {
+				return nil, err
+			}
+			c[i] = bundled
+		}
+	}
+	return v, nil
+}
+
+func (b *bundler) bundleReference(file, ref, kind string) (interface{}, error) {
+	if strings.HasPrefix(ref, "#") && file == b.rootFile {
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+	target, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i != -1 {
+		target, fragment = ref[:i], ref[i+1:]
+	}
+	targetFile := file
+	if target != "" {
+		targetFile = filepath.Join(filepath.Dir(file), filepath.FromSlash(target))
+	}
+	key := targetFile + "#" + fragment
+	if local, ok := b.refs[key]; ok {
+		return map[string]interface{}{"$ref": local}, nil
+	}
+	loaded, err := b.load(targetFile)
+	if err != nil {
+		return nil, err
+	}
+	value, err := evaluateJSONPointer(loaded, fragment)
+	if err != nil {
+		return nil, err
+	}
+	value = copyJSONValue(value)
+	tokens, _ := splitJSONPointer(fragment)
+	if len(tokens) == 3 && tokens[0] == "components" {
+		kind = tokens[1]
+	}
+	if kind == "" {
+		// the object cannot be a component, like a path item
+		return b.bundle(targetFile, value, kind)
+	}
+	name := strings.TrimSuffix(path.Base(filepath.ToSlash(target)), filepath.Ext(target))
+	if len(tokens) > 0 {
+		name = tokens[len(tokens)-1]
+	}
+	if targetFile == b.rootFile {
+		// a reference back to the root document
+		b.refs[key] = "#" + fragment
+		return map[string]interface{}{"$ref": "#" + fragment}, nil
+	}
+	local := b.addComponent(kind, name)
+	b.refs[key] = local
+	bundled, err := b.bundle(targetFile, value, kind)
+	if err != nil {
+		return nil, err
+	}
+	b.components(kind)[strings.TrimPrefix(local, "#/components/"+kind+"/")] = bundled
+	return map[string]interface{}{"$ref": local}, nil
+}
+
+// addComponent reserves an unused name of the component and returns the
+// reference to it.
+func (b *bundler) addComponent(kind, name string) string {
+	name = bundleNameReplacer.ReplaceAllString(name, "_")
+	components := b.components(kind)
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := components[unique]; !ok {
+			break
+		}
+		unique = name + strconv.Itoa(i)
+	}
+	components[unique] = nil
+	return componentRef(kind, unique)
+}
+
+func (b *bundler) components(kind string) map[string]interface{} {
+	components, ok := b.root["components"].(map[string]interface{})
+	if !ok {
+		components = map[string]interface{}{}
+		b.root["components"] = components
+	}
+	m, ok := components[kind].(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+		components[kind] = m
+	}
+	return m
+}
+
+// bundleNameReplacer matches the characters which cannot be used in the
+// names of the components.
+var bundleNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9\.\-_]`)
+
+// bundleNoReference is the kind of values which never contain references,
+// like examples of schemas.
+const bundleNoReference = "-"
+
+// bundleChildKind returns the kind of the component which the child value
+// of given key can be. The kind ending with "*" is a map or an array whose
+// elements are the components of the kind.
+func bundleChildKind(kind, key string) string {
+	if kind == bundleNoReference {
+		return kind
+	}
+	if strings.HasSuffix(kind, "*") {
+		return strings.TrimSuffix(kind, "*")
+	}
+	if kind == "schemas" {
+		switch key {
+		case "items", "not", "additionalProperties":
+			return "schemas"
+		case "properties", "allOf", "oneOf", "anyOf":
+			return "schemas*"
+		}
+		return bundleNoReference
+	}
+	switch key {
+	case "schema":
+		return "schemas"
+	case "requestBody":
+		return "requestBodies"
+	case "example", "value", "default", "enum":
+		return bundleNoReference
+	case "schemas", "parameters", "responses", "headers", "examples", "links", "callbacks", "requestBodies", "securitySchemes":
+		return key + "*"
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestBundleFile(t *testing.T) {
+	doc, err := openapi.BundleFile("test/bundle/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := sortedKeysOf(doc.Components.Schemas), []string{"Error", "Owner", "pet"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("schemas: %v != %v", got, expected)
+	}
+	if got := sortedKeysOf(doc.Components.Parameters); !reflect.DeepEqual(got, []string{"Limit"}) {
+		t.Errorf("parameters: %v != [Limit]", got)
+	}
+	if got := doc.Paths["/pets"].Get.Responses["200"].Content["application/json"].Schema.Items.Ref; got != "#/components/schemas/pet" {
+		t.Errorf("%s != #/components/schemas/pet", got)
+	}
+	if got := doc.Paths["/pets"].Get.Responses["default"].Ref; got != "#/components/responses/Error" {
+		t.Errorf("%s != #/components/responses/Error", got)
+	}
+	if got := doc.Components.Schemas["pet"].Properties["owner"].Ref; got != "#/components/schemas/Owner" {
+		t.Errorf("%s != #/components/schemas/Owner", got)
+	}
+	if got := doc.Components.Schemas["Owner"].Properties["pets"].Items.Ref; got != "#/components/schemas/pet" {
+		t.Errorf("%s != #/components/schemas/pet", got)
+	}
+	pathItem := doc.Paths["/pets/{id}"]
+	if pathItem == nil || pathItem.Get == nil || pathItem.Get.OperationID != "getPet" {
+		t.Fatalf("path item should be inlined: %+v", pathItem)
+	}
+	if got := pathItem.Get.Responses["200"].Content["application/json"].Schema.Ref; got != "#/components/schemas/pet" {
+		t.Errorf("%s != #/components/schemas/pet", got)
+	}
+}
+
+func TestBundleFile_NotFound(t *testing.T) {
+	if _, err := openapi.BundleFile("test/bundle/missing.yaml"); err == nil {
+		t.Error("error should be returned")
+	}
+}
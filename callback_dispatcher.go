@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// CallbackDispatcher sends the outbound requests declared in
+// operation.callbacks.
+type CallbackDispatcher struct {
+	// Document is used to resolve references in the callbacks.
+	Document *Document
+	// Client is used to send the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// MaxRetries is the number of retries after the first attempt.
+	// A request is retried when it fails or the response status is 429 or 5XX.
+	MaxRetries int
+	// Backoff returns the duration to wait before the retry.
+	// attempt starts from 1. If nil, exponential backoff from 100ms is used.
+	Backoff func(attempt int) time.Duration
+}
+
+func defaultBackoff(attempt int) time.Duration {
+	return 100 * time.Millisecond << uint(attempt-1)
+}
+
+// Dispatch sends the callback requests of the callback named name in
+// the operation. Each callback expression is evaluated against rc, which is
+// the triggering request, and each operation in the callback path item is
+// requested with given body. The body is validated against the schema of
+// the operation's request body before sending.
+// Returned responses are in order of the expressions and the methods, and
+// the caller must close their bodies.
+func (d *CallbackDispatcher) Dispatch(ctx context.Context, op *Operation, name string, rc *RuntimeContext, body interface{}) ([]*http.Response, error) {
+	callback, ok := op.Callbacks[name]
+	if !ok || callback == nil {
+		return nil, ErrCallbackNotFound
+	}
+	var exprs []string
+	for expr := range *callback {
+		exprs = append(exprs, expr)
+	}
+	sort.Strings(exprs)
+
+	var resps []*http.Response
+	for _, expr := range exprs {
+		pathItem := (*callback)[expr]
+		if pathItem == nil {
+			continue
+		}
+		u, err := ExpandRuntimeExpression(expr, rc)
+		if err != nil {
+			closeResponses(resps)
+			return nil, err
+		}
+		for _, method := range methods {
+			cbOp := pathItem.GetOperationByMethod(method)
+			if cbOp == nil {
+				continue
+			}
+			contentType, payload, err := encodeRequestBody(d.Document, cbOp.RequestBody, body)
+			if err != nil {
+				closeResponses(resps)
+				return nil, err
+			}
+			resp, err := d.send(ctx, method, u, contentType, payload)
+			if err != nil {
+				closeResponses(resps)
+				return nil, err
+			}
+			resps = append(resps, resp)
+		}
+	}
+	return resps, nil
+}
+
+func (d *CallbackDispatcher) send(ctx context.Context, method, u, contentType string, payload []byte) (*http.Response, error) {
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	backoff := d.Backoff
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff(attempt)):
+			}
+		}
+		req, err := http.NewRequest(method, u, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := client.Do(req)
+		if attempt >= d.MaxRetries {
+			return resp, err
+		}
+		if err == nil {
+			if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+				return resp, nil
+			}
+			resp.Body.Close()
+		}
+	}
+}
+
+func closeResponses(resps []*http.Response) {
+	for _, resp := range resps {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,119 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func callbackOperation() *openapi.Operation {
+	return &openapi.Operation{
+		Callbacks: map[string]*openapi.Callback{
+			"onEvent": &openapi.Callback{
+				"{$request.body#/callbackUrl}/events": &openapi.PathItem{
+					Post: &openapi.Operation{
+						RequestBody: &openapi.RequestBody{
+							Required: true,
+							Content: map[string]*openapi.MediaType{
+								"application/json": &openapi.MediaType{
+									Schema: &openapi.Schema{
+										Type:       "object",
+										Required:   []string{"message"},
+										Properties: map[string]*openapi.Schema{"message": &openapi.Schema{Type: "string"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCallbackDispatcher_Dispatch(t *testing.T) {
+	var count int32
+	var received map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/events" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type: %s", ct)
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &received); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	rc := &openapi.RuntimeContext{
+		Request:     httptest.NewRequest(http.MethodPost, "/subscribe", nil),
+		RequestBody: []byte(`{"callbackUrl": "` + srv.URL + `"}`),
+	}
+	dispatcher := &openapi.CallbackDispatcher{
+		Client:     srv.Client(),
+		MaxRetries: 2,
+		Backoff:    func(int) time.Duration { return time.Millisecond },
+	}
+	resps, err := dispatcher.Dispatch(context.Background(), callbackOperation(), "onEvent", rc, map[string]interface{}{"message": "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, resp := range resps {
+			resp.Body.Close()
+		}
+	}()
+	if len(resps) != 1 || resps[0].StatusCode != http.StatusNoContent {
+		t.Errorf("unexpected responses: %+v", resps)
+	}
+	if count != 2 {
+		t.Errorf("request should be retried once, but %d requests", count)
+	}
+	if expect := map[string]interface{}{"message": "hello"}; !reflect.DeepEqual(received, expect) {
+		t.Errorf("%+v != %+v", received, expect)
+	}
+}
+
+func TestCallbackDispatcher_DispatchError(t *testing.T) {
+	rc := &openapi.RuntimeContext{
+		Request:     httptest.NewRequest(http.MethodPost, "/subscribe", nil),
+		RequestBody: []byte(`{"callbackUrl": "http://example.com"}`),
+	}
+	dispatcher := &openapi.CallbackDispatcher{}
+	candidates := []struct {
+		label string
+		name  string
+		rc    *openapi.RuntimeContext
+		body  interface{}
+		err   error
+	}{
+		{"unknown callback", "foo", rc, nil, openapi.ErrCallbackNotFound},
+		{"invalid body", "onEvent", rc, map[string]interface{}{"message": 1}, openapi.ErrValueInvalid{Pointer: "/message", Reason: "must be string"}},
+		{"missing body", "onEvent", rc, nil, openapi.ErrRequired{Target: "request body"}},
+		{"expression not evaluable", "onEvent", &openapi.RuntimeContext{Request: rc.Request, RequestBody: []byte(`{}`)}, nil, openapi.ErrRuntimeExprNotFound{Expr: "$request.body#/callbackUrl"}},
+	}
+	for _, c := range candidates {
+		t.Run(c.label, func(t *testing.T) {
+			_, err := dispatcher.Dispatch(context.Background(), callbackOperation(), c.name, c.rc, c.body)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %v, but %v", c.err, err)
+			}
+		})
+	}
+}
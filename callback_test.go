@@ -3,7 +3,7 @@ package openapi_test
 import (
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestCallback_Validate(t *testing.T) {
@@ -17,7 +17,10 @@ func TestCallback_Validate(t *testing.T) {
 		{"with two expr", &openapi.Callback{"http://example.com/{$request.body#/user/uuid}/{$method}": &openapi.PathItem{}}, nil},
 		{"with invalid expr", &openapi.Callback{"https://example.com/{foo}": &openapi.PathItem{}}, openapi.ErrRuntimeExprFormat},
 		{"with empty flag", &openapi.Callback{"http://example.com/{$request.body#}": &openapi.PathItem{}}, openapi.ErrRuntimeExprFormat},
+		{"with header expr", &openapi.Callback{"http://example.com/{$request.header.X-Id}": &openapi.PathItem{}}, nil},
+		{"with invalid after header", &openapi.Callback{"http://example.com/{$request.header.X-Id}/{$value}": &openapi.PathItem{}}, openapi.ErrRuntimeExprFormat},
 		{"with second invalid", &openapi.Callback{"http://example.com/{$request.body#/user/uuid}/{$value}": &openapi.PathItem{}}, openapi.ErrRuntimeExprFormat},
+		{"with brace before expr", &openapi.Callback{"x}{$url}": &openapi.PathItem{}}, nil},
 	}
 	testValidater(t, candidates)
 }
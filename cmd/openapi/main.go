@@ -0,0 +1,482 @@
+// Command openapi is a command-line tool for OpenAPI Specification documents.
+//
+// Usage:
+//
+//	openapi <command> [flags] <file>...
+//
+// The commands are:
+//
+//	validate     validate the document
+//	lint         report the practices to avoid in the document
+//	bundle       bundle the files referred by the external references into one document
+//	dereference  replace the references with the referred objects
+//	diff         report the changes between two documents
+//	convert      convert between YAML and JSON, or Swagger 2.0 into OpenAPI 3.0
+//	filter       select the operations by tags, path prefix or extension
+//	mock         serve the examples of the responses
+//	docs         render the API reference in Markdown or HTML
+//
+// The reports of validate, lint and diff are written in JSON. The command
+// exits with 1 if the document is invalid, has lint issues or breaking
+// changes, and with 2 if the command cannot be run, like a missing file.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// exit codes
+const (
+	exitOK = iota
+	// exitFailure is used when the document is invalid, has lint issues
+	// or has breaking changes.
+	exitFailure
+	// exitError is used when the command cannot be run.
+	exitError
+)
+
+type command struct {
+	name  string
+	args  string
+	usage string
+	run   func(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error)
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"validate", "<file>", "validate the document", runValidate},
+		{"lint", "[-disable rules] <file>", "report the practices to avoid in the document", runLint},
+		{"bundle", "[-format yaml|json] [-o output] <file>", "bundle the files referred by the external references into one document", runBundle},
+		{"dereference", "[-format yaml|json] [-o output] <file>", "replace the references with the referred objects", runDereference},
+		{"diff", "<base> <revision>", "report the changes between two documents", runDiff},
+		{"convert", "[-format yaml|json] [-o output] <file>", "convert between YAML and JSON, or Swagger 2.0 into OpenAPI 3.0", runConvert},
+		{"filter", "[-tag tags] [-path-prefix prefix] [-extension name] [-exclude-deprecated] [-format yaml|json] [-o output] <file>", "select the operations by tags, path prefix or extension", runFilter},
+		{"mock", "[-addr address] <file>", "serve the examples of the responses", runMock},
+		{"docs", "[-format markdown|html] [-o output] <file>", "render the API reference in Markdown or HTML", runDocs},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "usage: openapi %s %s\n", cmd.name, cmd.args)
+			fs.PrintDefaults()
+		}
+		code, err := cmd.run(fs, args[1:], stdout)
+		if err != nil {
+			if err != flag.ErrHelp {
+				fmt.Fprintf(stderr, "openapi %s: %s\n", cmd.name, err)
+			}
+			return exitError
+		}
+		return code
+	}
+	usage(stderr)
+	return exitError
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: openapi <command> [flags] <file>...")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+var errArgs = errors.New("wrong number of arguments")
+
+// parse parses the flags and returns the n positional arguments.
+func parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != n {
+		fs.Usage()
+		return nil, errArgs
+	}
+	return fs.Args(), nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// output is the destination and the format of the commands which write
+// a document.
+type output struct {
+	format string
+	file   string
+}
+
+func (o *output) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "format", "", "output format, yaml or json (default: from the output or input file extension)")
+	fs.StringVar(&o.file, "o", "", "output file (default: stdout)")
+}
+
+func (o *output) write(doc *openapi.Document, input string, stdout io.Writer) error {
+	format := o.format
+	if format == "" {
+		name := o.file
+		if name == "" {
+			name = input
+		}
+		format = "yaml"
+		if strings.EqualFold(filepath.Ext(name), ".json") {
+			format = "json"
+		}
+	}
+	b, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	if !hasServers(b) {
+		// do not write the default server which Load sets
+		withoutServers := *doc
+		withoutServers.Servers = nil
+		doc = &withoutServers
+	}
+	switch format {
+	case "yaml":
+		b, err = yaml.Marshal(doc)
+	case "json":
+		var buf strings.Builder
+		err = writeJSON(&buf, doc)
+		b = []byte(buf.String())
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	if o.file == "" {
+		_, err = stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(o.file, b, 0644)
+}
+
+// hasServers reports whether the input document declares the servers, or
+// the host or the basePath which are converted into the servers if the
+// document is Swagger 2.0.
+func hasServers(b []byte) bool {
+	var v struct {
+		Swagger  string        `yaml:"swagger"`
+		Host     string        `yaml:"host"`
+		BasePath string        `yaml:"basePath"`
+		Servers  []interface{} `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return true
+	}
+	if v.Swagger != "" {
+		return v.Host != "" || v.BasePath != ""
+	}
+	return len(v.Servers) > 0
+}
+
+type validateResult struct {
+	File   string   `json:"file"`
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func runValidate(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	result := validateResult{File: args[0], Valid: true}
+	doc, err := openapi.LoadFile(args[0])
+	if err == nil {
+		err = doc.Validate()
+	}
+	if _, ok := err.(*os.PathError); ok {
+		return 0, err
+	}
+	if err != nil {
+		result.Valid = false
+		result.Errors = []string{err.Error()}
+	}
+	if err := writeJSON(stdout, result); err != nil {
+		return 0, err
+	}
+	if !result.Valid {
+		return exitFailure, nil
+	}
+	return exitOK, nil
+}
+
+type lintIssue struct {
+	Rule    string `json:"rule"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+type lintResult struct {
+	File   string      `json:"file"`
+	Issues []lintIssue `json:"issues"`
+}
+
+func runLint(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	disable := fs.String("disable", "", "comma-separated rules not to report")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := openapi.LoadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	issues, err := doc.Lint()
+	if err != nil {
+		return 0, err
+	}
+	disabled := map[string]bool{}
+	for _, rule := range strings.Split(*disable, ",") {
+		disabled[strings.TrimSpace(rule)] = true
+	}
+	result := lintResult{File: args[0], Issues: []lintIssue{}}
+	for _, issue := range issues {
+		if disabled[issue.Rule] {
+			continue
+		}
+		result.Issues = append(result.Issues, lintIssue{Rule: issue.Rule, Pointer: issue.Pointer, Message: issue.Message})
+	}
+	if err := writeJSON(stdout, result); err != nil {
+		return 0, err
+	}
+	if len(result.Issues) > 0 {
+		return exitFailure, nil
+	}
+	return exitOK, nil
+}
+
+func runBundle(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	var out output
+	out.register(fs)
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := openapi.BundleFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	return exitOK, out.write(doc, args[0], stdout)
+}
+
+func runDereference(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	var out output
+	out.register(fs)
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := openapi.LoadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	doc, err = doc.Dereference()
+	if err != nil {
+		return 0, err
+	}
+	return exitOK, out.write(doc, args[0], stdout)
+}
+
+type change struct {
+	Type     string `json:"type"`
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+type diffResult struct {
+	Base     string   `json:"base"`
+	Revision string   `json:"revision"`
+	Breaking int      `json:"breaking"`
+	Changes  []change `json:"changes"`
+}
+
+func runDiff(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	args, err := parse(fs, args, 2)
+	if err != nil {
+		return 0, err
+	}
+	base, err := openapi.LoadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	revision, err := openapi.LoadFile(args[1])
+	if err != nil {
+		return 0, err
+	}
+	changes, err := openapi.Diff(base, revision)
+	if err != nil {
+		return 0, err
+	}
+	result := diffResult{Base: args[0], Revision: args[1], Changes: []change{}}
+	for _, c := range changes {
+		result.Changes = append(result.Changes, change{Type: string(c.Type), Pointer: c.Pointer, Message: c.Message, Breaking: c.Breaking})
+		if c.Breaking {
+			result.Breaking++
+		}
+	}
+	if err := writeJSON(stdout, result); err != nil {
+		return 0, err
+	}
+	if result.Breaking > 0 {
+		return exitFailure, nil
+	}
+	return exitOK, nil
+}
+
+func runConvert(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	var out output
+	out.register(fs)
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	b, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	var version struct {
+		Swagger string `yaml:"swagger"`
+	}
+	if err := yaml.Unmarshal(b, &version); err != nil {
+		return 0, err
+	}
+	var doc *openapi.Document
+	if version.Swagger != "" {
+		doc, err = openapi.ConvertSwagger(b)
+	} else {
+		doc, err = openapi.Load(b)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if out.format == "" && out.file == "" {
+		// convert into the other format by default
+		out.format = "json"
+		if strings.EqualFold(filepath.Ext(args[0]), ".json") {
+			out.format = "yaml"
+		}
+	}
+	return exitOK, out.write(doc, args[0], stdout)
+}
+
+func runFilter(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	var out output
+	out.register(fs)
+	tags := fs.String("tag", "", "comma-separated tags of the operations to keep")
+	prefix := fs.String("path-prefix", "", "path prefix of the operations to keep")
+	extension := fs.String("extension", "", "specification extension which is true in the operations to keep")
+	excludeDeprecated := fs.Bool("exclude-deprecated", false, "remove the deprecated operations")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	var filters []openapi.OperationFilter
+	if *tags != "" {
+		filters = append(filters, openapi.TagFilter(strings.Split(*tags, ",")...))
+	}
+	if *prefix != "" {
+		filters = append(filters, openapi.PathPrefixFilter(*prefix))
+	}
+	if *extension != "" {
+		filters = append(filters, openapi.ExtensionFilter(*extension))
+	}
+	if *excludeDeprecated {
+		filters = append(filters, openapi.NotDeprecatedFilter())
+	}
+	doc, err := openapi.LoadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	doc, err = doc.Filter(openapi.AndFilter(filters...))
+	if err != nil {
+		return 0, err
+	}
+	return exitOK, out.write(doc, args[0], stdout)
+}
+
+func runMock(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	addr := fs.String("addr", ":4010", "address to listen on")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := openapi.LoadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(stdout, "serving mock of %s on %s\n", args[0], *addr)
+	return exitOK, http.ListenAndServe(*addr, openapi.NewMockHandler(doc))
+}
+
+func runDocs(fs *flag.FlagSet, args []string, stdout io.Writer) (int, error) {
+	format := fs.String("format", "", "output format, markdown or html (default: from the output file extension, or markdown)")
+	file := fs.String("o", "", "output file (default: stdout)")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	if *format == "" {
+		*format = "markdown"
+		if ext := strings.ToLower(filepath.Ext(*file)); ext == ".html" || ext == ".htm" {
+			*format = "html"
+		}
+	}
+	doc, err := openapi.LoadFile(args[0])
+	if err != nil {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	switch *format {
+	case "markdown":
+		err = doc.WriteMarkdown(&buf)
+	case "html":
+		err = doc.WriteHTML(&buf)
+	default:
+		return 0, fmt.Errorf("unknown format: %s", *format)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if *file == "" {
+		_, err = buf.WriteTo(stdout)
+		return exitOK, err
+	}
+	return exitOK, ioutil.WriteFile(*file, buf.Bytes(), 0644)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const invalidSpec = `
+openapi: 3.0.2
+info:
+  version: 1.0.0
+paths: {}
+`
+
+const swaggerSpec = `
+swagger: "2.0"
+info:
+  title: swagger
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: pets
+`
+
+const preciseSpec = `
+openapi: 3.0.2
+info:
+  title: precise
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    coordinate:
+      type: number
+      minimum: -90.123456
+      maximum: 3.14159265
+      default: 2.718281828
+      enum: [2.718281828, 1.41421356]
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	invalid := writeFile(t, dir, "invalid.yaml", invalidSpec)
+	swagger := writeFile(t, dir, "swagger.yaml", swaggerSpec)
+	precise := writeFile(t, dir, "precise.yaml", preciseSpec)
+
+	candidates := []struct {
+		label  string
+		args   []string
+		code   int
+		stdout string
+	}{
+		{"noCommand", nil, exitError, ""},
+		{"unknownCommand", []string{"foo"}, exitError, ""},
+		{"noFile", []string{"validate"}, exitError, ""},
+		{"missingFile", []string{"validate", "missing.yaml"}, exitError, ""},
+		{"valid", []string{"validate", "../../test/petstore.yaml"}, exitOK, `"valid": true`},
+		{"invalid", []string{"validate", invalid}, exitFailure, `info.title is required`},
+		{"lint", []string{"lint", "../../test/petstore.yaml"}, exitFailure, `"rule": "operation-tags"`},
+		{"lintDisabled", []string{"lint", "-disable", "operation-tags", "../../test/petstore.yaml"}, exitOK, `"issues": []`},
+		{"diffIdentical", []string{"diff", "../../test/petstore.yaml", "../../test/petstore.yaml"}, exitOK, `"breaking": 0`},
+		{"diffBreaking", []string{"diff", "../../test/petstore.yaml", "../../test/petstore-expanded.yaml"}, exitFailure, `"message": "response 201 is removed"`},
+		{"convertSwagger", []string{"convert", swagger}, exitOK, `"openapi": "3.0.2"`},
+		{"convertYAML", []string{"convert", "-format", "yaml", "../../test/petstore.yaml"}, exitOK, "openapi: 3.0.0"},
+		{"unknownFormat", []string{"convert", "-format", "xml", "../../test/petstore.yaml"}, exitError, ""},
+		{"bundle", []string{"bundle", "../../test/bundle/openapi.yaml"}, exitOK, "$ref: '#/components/schemas/pet'"},
+		{"convertDecimal", []string{"convert", precise}, exitOK, `"maximum": 3.14159265`},
+		{"convertNegativeDecimal", []string{"convert", precise}, exitOK, `"minimum": -90.123456`},
+		{"convertDefault", []string{"convert", precise}, exitOK, `"default": 2.718281828`},
+		{"convertEnum", []string{"convert", precise}, exitOK, `1.41421356`},
+		{"bundleDecimal", []string{"bundle", precise}, exitOK, "maximum: 3.14159265"},
+		{"bundleNegativeDecimal", []string{"bundle", precise}, exitOK, "minimum: -90.123456"},
+		{"dereference", []string{"dereference", "-format", "json", "../../test/petstore.yaml"}, exitOK, `"operationId": "listPets"`},
+		{"filter", []string{"filter", "-path-prefix", "/pets/", "../../test/petstore.yaml"}, exitOK, "operationId: showPetById"},
+		{"docsMarkdown", []string{"docs", "../../test/petstore.yaml"}, exitOK, "### GET /pets/{petId}"},
+		{"docsHTML", []string{"docs", "-format", "html", "../../test/petstore.yaml"}, exitOK, `<section id="tag-pets">`},
+		{"docsUnknownFormat", []string{"docs", "-format", "pdf", "../../test/petstore.yaml"}, exitError, ""},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(c.args, &stdout, &stderr)
+			if code != c.code {
+				t.Errorf("exit code %d != %d: %s", code, c.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), c.stdout) {
+				t.Errorf("%q is not in the output:\n%s", c.stdout, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_DefaultServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	precise := writeFile(t, dir, "precise.yaml", preciseSpec)
+	swagger := writeFile(t, dir, "swagger.yaml", swaggerSpec)
+
+	candidates := []struct {
+		label   string
+		args    []string
+		servers bool
+	}{
+		{"bundle", []string{"bundle", precise}, false},
+		{"convert", []string{"convert", precise}, false},
+		{"convertSwagger", []string{"convert", swagger}, false},
+		{"dereference", []string{"dereference", precise}, false},
+		{"filter", []string{"filter", precise}, false},
+		{"declared", []string{"filter", "../../test/petstore.yaml"}, true},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(c.args, &stdout, &stderr); code != exitOK {
+				t.Fatalf("exit code %d != %d: %s", code, exitOK, stderr.String())
+			}
+			if servers := strings.Contains(stdout.String(), "servers"); servers != c.servers {
+				t.Errorf("servers should be written: %t\n%s", c.servers, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_Output(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "petstore.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"filter", "-tag", "pets", "-o", output, "../../test/petstore.yaml"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("exit code %d != %d: %s", code, exitOK, stderr.String())
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(b) {
+		t.Errorf("output should be JSON: %s", b)
+	}
+	doc, err := openapi.Load(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+}
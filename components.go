@@ -1,18 +1,23 @@
 package openapi
 
+import "reflect"
+
 // codebeat:disable[TOO_MANY_IVARS]
 
 // Components Object
 type Components struct {
-	Schemas         map[string]*Schema
-	Responses       map[string]*Response
-	Parameters      map[string]*Parameter
-	Examples        map[string]*Example
-	RequestBodies   map[string]*RequestBody `yaml:"requestBodies"`
-	Headers         map[string]*Header
-	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
-	Links           map[string]*Link
-	Callbacks       map[string]*Callback
+	Schemas         map[string]*Schema         `yaml:"schemas,omitempty"`
+	Responses       map[string]*Response       `yaml:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `yaml:"parameters,omitempty"`
+	Examples        map[string]*Example        `yaml:"examples,omitempty"`
+	RequestBodies   map[string]*RequestBody    `yaml:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `yaml:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes,omitempty"`
+	Links           map[string]*Link           `yaml:"links,omitempty"`
+	Callbacks       map[string]*Callback       `yaml:"callbacks,omitempty"`
+
+	// schemaTypes are the names of the schemas registered by SchemaFor.
+	schemaTypes map[reflect.Type]string
 }
 
 // Validate the values of Components object.
@@ -77,8 +82,11 @@ func reduceComponentObjects(components Components) []validater {
 	for _, parameter := range components.Parameters {
 		validaters = append(validaters, parameter)
 	}
-
-	// example has no validation
+	for _, example := range components.Examples {
+		if example != nil {
+			validaters = append(validaters, example)
+		}
+	}
 
 	for _, reqBody := range components.RequestBodies {
 		validaters = append(validaters, reqBody)
@@ -4,9 +4,9 @@ package openapi
 
 // Contact Object
 type Contact struct {
-	Name  string
-	URL   string
-	Email string
+	Name  string `yaml:"name,omitempty"`
+	URL   string `yaml:"url,omitempty"`
+	Email string `yaml:"email,omitempty"`
 }
 
 // Validate the values of Contact object.
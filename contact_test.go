@@ -4,7 +4,7 @@ import (
 	"reflect"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// Decimal is a number of the numeric constraints of schemas, like minimum
+// or multipleOf. It keeps the decimal literal as json.Number does, so that
+// the constraints like "multipleOf: 0.01" are not rounded into binary
+// fractions. The empty Decimal means that the constraint is not set.
+type Decimal string
+
+// NewDecimal returns the shortest decimal which represents f.
+func NewDecimal(f float64) Decimal {
+	return Decimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// IsSet reports whether the decimal is set.
+func (d Decimal) IsSet() bool {
+	return d != ""
+}
+
+// Float64 returns the decimal as float64.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(string(d), 64)
+}
+
+func (d Decimal) String() string {
+	return string(d)
+}
+
+var decimalRegexp = regexp.MustCompile(`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+
+// rat returns the decimal as a rational number, or nil if the decimal
+// is not a decimal literal.
+func (d Decimal) rat() *big.Rat {
+	if !decimalRegexp.MatchString(string(d)) {
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// MarshalYAML implements yaml.Marshaler. The decimal is marshaled as
+// a number, not as a string.
+func (d Decimal) MarshalYAML() (interface{}, error) {
+	r := d.rat()
+	if r == nil {
+		return nil, ErrFormatInvalid{Target: "decimal " + string(d), Format: "number"}
+	}
+	if r.IsInt() {
+		if n := r.Num(); n.IsInt64() {
+			return n.Int64(), nil
+		} else if n.IsUint64() {
+			return n.Uint64(), nil
+		}
+	}
+	f, _ := r.Float64()
+	return f, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. The literal of the number
+// is kept as is.
+func (d *Decimal) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	switch v.(type) {
+	case int, int64, uint64, float64:
+	default:
+		return ErrFormatInvalid{Target: "decimal", Format: "number"}
+	}
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	if Decimal(s).rat() == nil {
+		// the literals like 0x10 are not decimal
+		f, _ := toFloat(v)
+		if s = NewDecimal(f).String(); Decimal(s).rat() == nil {
+			return ErrFormatInvalid{Target: "decimal " + s, Format: "number"}
+		}
+	}
+	*d = Decimal(s)
+	return nil
+}
+
+// toRat returns the numeric value as a rational number. The float values
+// are converted via their shortest decimal representations, so that 0.3
+// is 3/10 and not the nearest binary fraction.
+func toRat(value interface{}) (*big.Rat, bool) {
+	if n, ok := value.(json.Number); ok {
+		return new(big.Rat).SetString(string(n))
+	}
+	f, ok := toFloat(value)
+	if !ok {
+		return nil, false
+	}
+	r := NewDecimal(f).rat()
+	return r, r != nil
+}
@@ -0,0 +1,57 @@
+package openapi_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestDecimal(t *testing.T) {
+	candidates := []struct {
+		label    string
+		in       string
+		expected openapi.Decimal
+		out      string
+	}{
+		{"integer", "minimum: 3", "3", "minimum: 3\n"},
+		{"zero", "minimum: 0", "0", "minimum: 0\n"},
+		{"fraction", "minimum: 0.5", "0.5", "minimum: 0.5\n"},
+		{"exponent", "minimum: 1e-2", "1e-2", "minimum: 0.01\n"},
+		{"hex", "minimum: 0x10", "16", "minimum: 16\n"},
+		{"beyond int64", "minimum: 18446744073709551615", "18446744073709551615", "minimum: 18446744073709551615\n"},
+		{"large exponent", "minimum: 1e+30", "1e+30", "minimum: 1e+30\n"},
+		{"double precision", "minimum: 3.14159265", "3.14159265", "minimum: 3.14159265\n"},
+		{"unset", "type: number", "", "type: number\n"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			var schema openapi.Schema
+			if err := yaml.Unmarshal([]byte(c.in), &schema); err != nil {
+				t.Fatal(err)
+			}
+			if schema.Minimum != c.expected {
+				t.Errorf("%s != %s", schema.Minimum, c.expected)
+			}
+			if schema.Minimum.IsSet() != (c.expected != "") {
+				t.Errorf("IsSet should be %t", c.expected != "")
+			}
+			b, err := yaml.Marshal(schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != c.out {
+				t.Errorf("%q != %q", b, c.out)
+			}
+		})
+	}
+	var schema openapi.Schema
+	if err := yaml.Unmarshal([]byte("minimum: '3'"), &schema); err == nil || !strings.Contains(err.Error(), "number") {
+		t.Errorf("string should not be a decimal: %v", err)
+	}
+	if d := openapi.NewDecimal(0.01); d != "0.01" {
+		t.Errorf("%s != 0.01", d)
+	}
+}
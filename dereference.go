@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dereference returns a new document whose references are replaced with
+// the referred objects. The references which refer themselves recursively,
+// like a schema of a tree node, are left as they are, so that the components
+// are kept in the document. The free-form values, like examples and
+// defaults, are kept as they are even if they have "$ref" keys.
+// The document itself is not modified.
+func (doc *Document) Dereference() (*Document, error) {
+	v, err := doc.toValue()
+	if err != nil {
+		return nil, err
+	}
+	d := dereferencer{root: copyJSONValue(v), freeForm: doc.freeFormPointers()}
+	// the references are resolved in the copy, which is not dereferenced
+	v, err = d.dereference(v, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return documentFromValue(v)
+}
+
+// freeFormPointers returns the JSON pointers of the values in the document
+// which are not OpenAPI objects, like examples.
+func (doc *Document) freeFormPointers() map[string]bool {
+	pointers := map[string]bool{}
+	doc.Visit(func(ptr string, node, parent interface{}) error {
+		var keys []string
+		switch node.(type) {
+		case *Schema:
+			keys = []string{"example", "default", "enum"}
+		case *MediaType, *Parameter, *Header:
+			keys = []string{"example"}
+		case *Example:
+			keys = []string{"value"}
+		case *Link:
+			keys = []string{"parameters", "requestBody"}
+		}
+		for _, key := range keys {
+			pointers[childPointer(ptr, key)] = true
+		}
+		return nil
+	})
+	return pointers
+}
+
+// dereferencer replaces the reference objects with the values referred in
+// the root value, except for the values at the free-form pointers.
+type dereferencer struct {
+	root     interface{}
+	freeForm map[string]bool
+}
+
+// dereference replaces the reference objects in the value at the pointer.
+// The pointer is the location of the value in the root, and the refs are
+// the references being dereferenced, which are used to detect recursive
+// references.
+func (d dereferencer) dereference(v interface{}, ptr string, refs []string) (interface{}, error) {
+	if d.freeForm[ptr] {
+		return v, nil
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := c["$ref"].(string); ok {
+			if containsString(refs, ref) {
+				return c, nil
+			}
+			if !strings.HasPrefix(ref, "#") {
+				return nil, ErrFormatInvalid{Target: "reference " + ref}
+			}
+			target, err := evaluateJSONPointer(d.root, ref[1:])
+			if err != nil {
+				return nil, err
+			}
+			return d.dereference(copyJSONValue(target), ref[1:], childTokens(refs, ref))
+		}
+		for k, val := range c {
+			deref, err := d.dereference(val, childPointer(ptr, k), refs)
+			if err != nil {
+				return nil, err
+			}
+			c[k] = deref
+		}
+	case []interface{}:
+		for i, val := range c {
+			deref, err := d.dereference(val, ptr+"/"+strconv.Itoa(i), refs)
+			if err != nil {
+				return nil, err
+			}
+			c[i] = deref
+		}
+	}
+	return v, nil
+}
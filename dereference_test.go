@@ -0,0 +1,113 @@
+package openapi_test
+
+import (
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestDocument_Dereference(t *testing.T) {
+	doc, err := openapi.Load([]byte(pruneSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Components.Schemas["Node"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"children": &openapi.Schema{Type: "array", Items: &openapi.Schema{Ref: "#/components/schemas/Node"}},
+		},
+	}
+	doc.Components.Schemas["Cat"].Properties = map[string]*openapi.Schema{
+		"tree": &openapi.Schema{Ref: "#/components/schemas/Node"},
+	}
+	deref, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := deref.Paths["/pets"].Get
+	if op.Parameters[0].Ref != "" || op.Parameters[0].Name != "limit" || op.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("parameter should be dereferenced: %+v", op.Parameters[0])
+	}
+	pets := op.Responses["200"]
+	if pets.Ref != "" || pets.Description != "pets" {
+		t.Fatalf("response should be dereferenced: %+v", pets)
+	}
+	schema := pets.Content["application/json"].Schema
+	if schema.Type != "array" || schema.Items.Ref != "" || schema.Items.OneOf[0].Type != "object" {
+		t.Errorf("schema should be dereferenced: %+v", schema)
+	}
+	if pets.Content["application/json"].Examples["pets"].Ref != "" {
+		t.Error("example should be dereferenced")
+	}
+	tree := schema.Items.OneOf[0].Properties["tree"]
+	if tree.Type != "object" {
+		t.Errorf("recursive schema should be dereferenced once: %+v", tree)
+	}
+	if got := tree.Properties["children"].Items.Ref; got != "#/components/schemas/Node" {
+		t.Errorf("recursive reference should be kept: %s", got)
+	}
+	if err := deref.Validate(); err != nil {
+		t.Error(err)
+	}
+	if doc.Paths["/pets"].Get.Parameters[0].Ref == "" {
+		t.Error("original document should not be modified")
+	}
+}
+
+func TestDocument_Dereference_FreeForm(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: free-form
+  version: 1.0.0
+paths:
+  /refs:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Ref'
+      responses:
+        '200':
+          description: a reference
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Ref'
+              example:
+                $ref: '#/components/schemas/Ref'
+components:
+  parameters:
+    Ref:
+      name: ref
+      in: query
+      schema:
+        type: object
+        default:
+          $ref: '#/components/schemas/Ref'
+  schemas:
+    Ref:
+      type: object
+      properties:
+        $ref:
+          type: string
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deref, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := deref.Paths["/refs"].Get
+	mt := op.Responses["200"].Content["application/json"]
+	if mt.Schema.Ref != "" || mt.Schema.Type != "object" {
+		t.Errorf("schema should be dereferenced: %+v", mt.Schema)
+	}
+	example, ok := mt.Example.(map[interface{}]interface{})
+	if !ok || example["$ref"] != "#/components/schemas/Ref" {
+		t.Errorf("example should be kept: %#v", mt.Example)
+	}
+	def, ok := op.Parameters[0].Schema.Default.(map[interface{}]interface{})
+	if !ok || def["$ref"] != "#/components/schemas/Ref" {
+		t.Errorf("default in the referred parameter should be kept: %#v", op.Parameters[0].Schema.Default)
+	}
+}
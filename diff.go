@@ -0,0 +1,286 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ChangeType is the type of a change found by Diff.
+type ChangeType string
+
+// ChangeTypes
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is a difference between two documents.
+type Change struct {
+	Type ChangeType
+	// Pointer is the JSON pointer to the changed object, in the revision
+	// document if the object is added, otherwise in the base document.
+	Pointer string
+	Message string
+	// Breaking reports whether the change can break the existing clients,
+	// like removing an operation or adding a required parameter.
+	Breaking bool
+}
+
+// Diff compares the operations, the component schemas and the security
+// schemes of the documents, and returns the changes from the base document
+// to the revision document.
+func Diff(base, revision *Document) ([]*Change, error) {
+	d := &differ{base: base, revision: revision}
+	if err := d.operations(); err != nil {
+		return nil, err
+	}
+	if err := d.schemas(); err != nil {
+		return nil, err
+	}
+	d.securitySchemes()
+	return d.changes, nil
+}
+
+type differ struct {
+	base     *Document
+	revision *Document
+	changes  []*Change
+}
+
+func (d *differ) add(typ ChangeType, ptr string, breaking bool, msg string) {
+	d.changes = append(d.changes, &Change{Type: typ, Pointer: ptr, Message: msg, Breaking: breaking})
+}
+
+func operationPointer(eop *EffectiveOperation) string {
+	return childPointer(childPointer("/paths", eop.Path), strings.ToLower(eop.Method))
+}
+
+func (d *differ) operations() error {
+	baseOps, err := d.base.EffectiveOperations()
+	if err != nil {
+		return err
+	}
+	revisionOps, err := d.revision.EffectiveOperations()
+	if err != nil {
+		return err
+	}
+	revisionByKey := map[string]*EffectiveOperation{}
+	for _, eop := range revisionOps {
+		revisionByKey[eop.Method+" "+eop.Path] = eop
+	}
+	baseByKey := map[string]*EffectiveOperation{}
+	for _, b := range baseOps {
+		baseByKey[b.Method+" "+b.Path] = b
+		r, ok := revisionByKey[b.Method+" "+b.Path]
+		if !ok {
+			d.add(ChangeRemoved, operationPointer(b), true, "operation "+b.Method+" "+b.Path+" is removed")
+			continue
+		}
+		if err := d.operation(operationPointer(b), b, r); err != nil {
+			return err
+		}
+	}
+	for _, r := range revisionOps {
+		if _, ok := baseByKey[r.Method+" "+r.Path]; !ok {
+			d.add(ChangeAdded, operationPointer(r), false, "operation "+r.Method+" "+r.Path+" is added")
+		}
+	}
+	return nil
+}
+
+func (d *differ) operation(ptr string, b, r *EffectiveOperation) error {
+	if err := d.parameters(ptr, b.Parameters, r.Parameters); err != nil {
+		return err
+	}
+	d.requestBody(childPointer(ptr, "requestBody"), b.RequestBody, r.RequestBody)
+	d.responses(childPointer(ptr, "responses"), b.Responses, r.Responses)
+	return nil
+}
+
+func parameterKey(parameter *Parameter) string {
+	return string(parameter.In) + " parameter " + parameter.Name
+}
+
+func (d *differ) parameters(ptr string, b, r []*Parameter) error {
+	ptr = childPointer(ptr, "parameters")
+	revisionByKey := map[string]*Parameter{}
+	for _, p := range r {
+		revisionByKey[parameterKey(p)] = p
+	}
+	baseByKey := map[string]*Parameter{}
+	for i, bp := range b {
+		key := parameterKey(bp)
+		baseByKey[key] = bp
+		bptr := ptr + "/" + strconv.Itoa(i)
+		rp, ok := revisionByKey[key]
+		if !ok {
+			d.add(ChangeRemoved, bptr, false, key+" is removed")
+			continue
+		}
+		if !bp.Required && rp.Required {
+			d.add(ChangeModified, bptr, true, key+" becomes required")
+		}
+		bs, err := resolveSchema(d.base, bp.Schema)
+		if err != nil {
+			return err
+		}
+		rs, err := resolveSchema(d.revision, rp.Schema)
+		if err != nil {
+			return err
+		}
+		if bs != nil && rs != nil && bs.Type != rs.Type {
+			d.add(ChangeModified, bptr, true, key+" type is changed from "+bs.Type+" to "+rs.Type)
+		}
+	}
+	for i, rp := range r {
+		key := parameterKey(rp)
+		if _, ok := baseByKey[key]; !ok {
+			msg := key + " is added"
+			if rp.Required {
+				msg = "required " + msg
+			}
+			d.add(ChangeAdded, ptr+"/"+strconv.Itoa(i), rp.Required, msg)
+		}
+	}
+	return nil
+}
+
+func (d *differ) requestBody(ptr string, b, r *RequestBody) {
+	switch {
+	case b == nil && r == nil:
+		return
+	case b == nil:
+		d.add(ChangeAdded, ptr, r.Required, "request body is added")
+		return
+	case r == nil:
+		d.add(ChangeRemoved, ptr, false, "request body is removed")
+		return
+	}
+	if !b.Required && r.Required {
+		d.add(ChangeModified, ptr, true, "request body becomes required")
+	}
+	d.content(childPointer(ptr, "content"), b.Content, r.Content, "request")
+}
+
+func (d *differ) responses(ptr string, b, r Responses) {
+	for _, status := range sortedMapKeys(b) {
+		rr, ok := r[status]
+		if !ok {
+			d.add(ChangeRemoved, childPointer(ptr, status), true, "response "+status+" is removed")
+			continue
+		}
+		if br := b[status]; br != nil && rr != nil {
+			d.content(childPointer(childPointer(ptr, status), "content"), br.Content, rr.Content, "response")
+		}
+	}
+	for _, status := range sortedMapKeys(r) {
+		if _, ok := b[status]; !ok {
+			d.add(ChangeAdded, childPointer(ptr, status), false, "response "+status+" is added")
+		}
+	}
+}
+
+func (d *differ) content(ptr string, b, r map[string]*MediaType, target string) {
+	for _, mediaType := range sortedMapKeys(b) {
+		if _, ok := r[mediaType]; !ok {
+			d.add(ChangeRemoved, childPointer(ptr, mediaType), true, target+" media type "+mediaType+" is removed")
+		}
+	}
+	for _, mediaType := range sortedMapKeys(r) {
+		if _, ok := b[mediaType]; !ok {
+			d.add(ChangeAdded, childPointer(ptr, mediaType), false, target+" media type "+mediaType+" is added")
+		}
+	}
+}
+
+func (d *differ) schemas() error {
+	var b, r map[string]*Schema
+	if d.base.Components != nil {
+		b = d.base.Components.Schemas
+	}
+	if d.revision.Components != nil {
+		r = d.revision.Components.Schemas
+	}
+	for _, name := range sortedMapKeys(b) {
+		ptr := componentRef(string(SchemaComponent), name)[1:]
+		rs, ok := r[name]
+		if !ok {
+			d.add(ChangeRemoved, ptr, true, "schema "+name+" is removed")
+			continue
+		}
+		if err := d.schema(ptr, name, b[name], rs); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedMapKeys(r) {
+		if _, ok := b[name]; !ok {
+			d.add(ChangeAdded, componentRef(string(SchemaComponent), name)[1:], false, "schema "+name+" is added")
+		}
+	}
+	return nil
+}
+
+func (d *differ) schema(ptr, name string, b, r *Schema) error {
+	if b == nil || r == nil {
+		return nil
+	}
+	n := len(d.changes)
+	if b.Type != r.Type {
+		d.add(ChangeModified, ptr, true, "schema "+name+" type is changed from "+b.Type+" to "+r.Type)
+	}
+	for _, prop := range sortedMapKeys(b.Properties) {
+		if _, ok := r.Properties[prop]; !ok {
+			d.add(ChangeRemoved, childPointer(childPointer(ptr, "properties"), prop), true, "property "+prop+" of schema "+name+" is removed")
+		}
+	}
+	for _, prop := range sortedMapKeys(r.Properties) {
+		if _, ok := b.Properties[prop]; !ok {
+			d.add(ChangeAdded, childPointer(childPointer(ptr, "properties"), prop), containsString(r.Required, prop), "property "+prop+" of schema "+name+" is added")
+		}
+	}
+	for _, prop := range r.Required {
+		if !containsString(b.Required, prop) {
+			if _, ok := b.Properties[prop]; ok {
+				d.add(ChangeModified, childPointer(childPointer(ptr, "properties"), prop), true, "property "+prop+" of schema "+name+" becomes required")
+			}
+		}
+	}
+	if len(d.changes) > n {
+		return nil
+	}
+	// report the other changes as a modification of the schema
+	bv, err := toGenericValue(b)
+	if err != nil {
+		return err
+	}
+	rv, err := toGenericValue(r)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(bv, rv) {
+		d.add(ChangeModified, ptr, false, "schema "+name+" is modified")
+	}
+	return nil
+}
+
+func (d *differ) securitySchemes() {
+	var b, r map[string]*SecurityScheme
+	if d.base.Components != nil {
+		b = d.base.Components.SecuritySchemes
+	}
+	if d.revision.Components != nil {
+		r = d.revision.Components.SecuritySchemes
+	}
+	for _, name := range sortedMapKeys(b) {
+		if _, ok := r[name]; !ok {
+			d.add(ChangeRemoved, componentRef(string(SecuritySchemeComponent), name)[1:], true, "security scheme "+name+" is removed")
+		}
+	}
+	for _, name := range sortedMapKeys(r) {
+		if _, ok := b[name]; !ok {
+			d.add(ChangeAdded, componentRef(string(SecuritySchemeComponent), name)[1:], false, "security scheme "+name+" is added")
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const diffBaseSpec = `
+openapi: 3.0.2
+info:
+  title: diff
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        '404':
+          description: not found
+    post:
+      operationId: createPet
+      responses:
+        '201':
+          description: created
+  /owners:
+    get:
+      operationId: listOwners
+      responses:
+        '200':
+          description: owners
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+    Owner:
+      type: object
+    Legacy:
+      type: string
+  securitySchemes:
+    basic:
+      type: http
+      scheme: basic
+`
+
+const diffRevisionSpec = `
+openapi: 3.0.2
+info:
+  title: diff
+  version: 2.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: string
+        - name: owner
+          in: query
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        '400':
+          description: bad request
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: created
+  /stores:
+    get:
+      operationId: listStores
+      responses:
+        '200':
+          description: stores
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+    Owner:
+      type: object
+      description: owner of pets
+    Store:
+      type: object
+`
+
+func TestDiff(t *testing.T) {
+	base, err := openapi.Load([]byte(diffBaseSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision, err := openapi.Load([]byte(diffRevisionSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := openapi.Diff(base, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*openapi.Change{
+		{Type: openapi.ChangeRemoved, Pointer: "/paths/~1owners/get", Message: "operation GET /owners is removed", Breaking: true},
+		{Type: openapi.ChangeModified, Pointer: "/paths/~1pets/get/parameters/0", Message: "query parameter limit becomes required", Breaking: true},
+		{Type: openapi.ChangeModified, Pointer: "/paths/~1pets/get/parameters/0", Message: "query parameter limit type is changed from integer to string", Breaking: true},
+		{Type: openapi.ChangeRemoved, Pointer: "/paths/~1pets/get/parameters/1", Message: "query parameter cursor is removed"},
+		{Type: openapi.ChangeAdded, Pointer: "/paths/~1pets/get/parameters/1", Message: "required query parameter owner is added", Breaking: true},
+		{Type: openapi.ChangeRemoved, Pointer: "/paths/~1pets/get/responses/200/content/application~1xml", Message: "response media type application/xml is removed", Breaking: true},
+		{Type: openapi.ChangeRemoved, Pointer: "/paths/~1pets/get/responses/404", Message: "response 404 is removed", Breaking: true},
+		{Type: openapi.ChangeAdded, Pointer: "/paths/~1pets/get/responses/400", Message: "response 400 is added"},
+		{Type: openapi.ChangeAdded, Pointer: "/paths/~1pets/post/requestBody", Message: "request body is added", Breaking: true},
+		{Type: openapi.ChangeAdded, Pointer: "/paths/~1stores/get", Message: "operation GET /stores is added"},
+		{Type: openapi.ChangeRemoved, Pointer: "/components/schemas/Legacy", Message: "schema Legacy is removed", Breaking: true},
+		{Type: openapi.ChangeModified, Pointer: "/components/schemas/Owner", Message: "schema Owner is modified"},
+		{Type: openapi.ChangeRemoved, Pointer: "/components/schemas/Pet/properties/tag", Message: "property tag of schema Pet is removed", Breaking: true},
+		{Type: openapi.ChangeAdded, Pointer: "/components/schemas/Pet/properties/age", Message: "property age of schema Pet is added"},
+		{Type: openapi.ChangeModified, Pointer: "/components/schemas/Pet/properties/name", Message: "property name of schema Pet becomes required", Breaking: true},
+		{Type: openapi.ChangeAdded, Pointer: "/components/schemas/Store", Message: "schema Store is added"},
+		{Type: openapi.ChangeRemoved, Pointer: "/components/securitySchemes/basic", Message: "security scheme basic is removed", Breaking: true},
+	}
+	if len(changes) != len(expected) {
+		for _, c := range changes {
+			t.Logf("%+v", c)
+		}
+		t.Fatalf("%d changes are found, expected %d", len(changes), len(expected))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(changes[i], expected[i]) {
+			t.Errorf("%+v != %+v", changes[i], expected[i])
+		}
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	base, err := openapi.Load([]byte(diffBaseSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := openapi.Diff(base, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("no changes should be found: %v", changes)
+	}
+}
@@ -1,11 +1,16 @@
 package openapi
 
+import (
+	"sort"
+	"strings"
+)
+
 // codebeat:disable[TOO_MANY_IVARS]
 
 // Discriminator Object
 type Discriminator struct {
-	PropertyName string `yaml:"propertyName"`
-	Mapping      map[string]string
+	PropertyName string            `yaml:"propertyName,omitempty"`
+	Mapping      map[string]string `yaml:"mapping,omitempty"`
 }
 
 // Validate the values of Descriminator object.
@@ -15,3 +20,123 @@ func (discriminator Discriminator) Validate() error {
 	}
 	return nil
 }
+
+// ResolveDiscriminator returns the concrete schema for the value, which is
+// selected by the discriminator of the schema.
+// The discriminator property value is looked up in discriminator.mapping,
+// which values are schema names or references. If not mapped, the value is
+// matched with the component names of the schemas in oneOf or anyOf, or
+// the component names in the document if neither oneOf nor anyOf is set.
+func ResolveDiscriminator(root *Document, schema *Schema, value interface{}) (*Schema, error) {
+	schema, err := resolveSchema(root, schema)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil || schema.Discriminator == nil {
+		return nil, ErrRequired{Target: "schema.discriminator"}
+	}
+	propertyName := schema.Discriminator.PropertyName
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, ErrValueInvalid{Reason: "must be object"}
+	}
+	v, ok := obj[propertyName].(string)
+	if !ok {
+		return nil, ErrValueInvalid{Reason: "missing required property " + propertyName}
+	}
+	ref, ok := schema.discriminatorRef(root, v)
+	if !ok {
+		return nil, ErrUnknownDiscriminatorValue{Value: v}
+	}
+	return ResolveSchema(root, ref)
+}
+
+// mappingRef converts a value of discriminator.mapping into a reference.
+func mappingRef(target string) string {
+	if strings.HasPrefix(target, "#") {
+		return target
+	}
+	return "#/components/schemas/" + target
+}
+
+// discriminatorRef returns the reference to the schema for the
+// discriminator value.
+func (schema *Schema) discriminatorRef(root *Document, v string) (string, bool) {
+	if target, ok := schema.Discriminator.Mapping[v]; ok {
+		return mappingRef(target), true
+	}
+	candidates := append(append([]*Schema{}, schema.OneOf...), schema.AnyOf...)
+	if len(candidates) == 0 {
+		if root != nil && root.Components != nil {
+			if _, ok := root.Components.Schemas[v]; ok {
+				return "#/components/schemas/" + v, true
+			}
+		}
+		return "", false
+	}
+	for _, s := range candidates {
+		if s != nil && s.Ref != "" && refName(s) == v {
+			return s.Ref, true
+		}
+	}
+	return "", false
+}
+
+// validateDiscriminator validates every target schema of the discriminator
+// exists and declares the discriminator property as required.
+// The targets are the values of discriminator.mapping and the schemas in
+// oneOf and anyOf.
+func (schema *Schema) validateDiscriminator(root *Document) error {
+	if schema.Discriminator == nil {
+		return nil
+	}
+	var refs []string
+	for _, target := range schema.Discriminator.Mapping {
+		refs = append(refs, mappingRef(target))
+	}
+	sort.Strings(refs)
+	var targets []*Schema
+	for _, ref := range refs {
+		target, err := ResolveSchema(root, ref)
+		if err != nil {
+			return ErrDiscriminatorTargetNotFound{Target: ref}
+		}
+		targets = append(targets, target)
+	}
+	targets = append(append(targets, schema.OneOf...), schema.AnyOf...)
+	for _, target := range targets {
+		ok, err := requiresProperty(root, target, schema.Discriminator.PropertyName, map[*Schema]bool{})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrDiscriminatorPropertyNotRequired{Property: schema.Discriminator.PropertyName}
+		}
+	}
+	return nil
+}
+
+// requiresProperty reports whether the schema or its allOf schemas
+// declare the property as required.
+func requiresProperty(root *Document, schema *Schema, property string, visited map[*Schema]bool) (bool, error) {
+	schema, err := resolveSchema(root, schema)
+	if err != nil {
+		return false, err
+	}
+	if schema == nil || visited[schema] {
+		return false, nil
+	}
+	visited[schema] = true
+	for _, r := range schema.Required {
+		if r == property {
+			return true, nil
+		}
+	}
+	for _, s := range schema.AllOf {
+		ok, err := requiresProperty(root, s, property, visited)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
@@ -1,9 +1,11 @@
 package openapi_test
 
 import (
+	"reflect"
+	"strconv"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestDiscriminator_Validate(t *testing.T) {
@@ -13,3 +15,108 @@ func TestDiscriminator_Validate(t *testing.T) {
 	}
 	testValidater(t, candidates)
 }
+
+func discriminatorDocument() *openapi.Document {
+	return &openapi.Document{
+		Version: "3.0.0",
+		Info:    &openapi.Info{Title: "foo", Version: "1.0"},
+		Paths:   openapi.Paths{},
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Pet": &openapi.Schema{
+					Type:     "object",
+					Required: []string{"petType"},
+					Properties: map[string]*openapi.Schema{
+						"petType": &openapi.Schema{Type: "string"},
+					},
+					Discriminator: &openapi.Discriminator{PropertyName: "petType"},
+				},
+				"Cat": &openapi.Schema{
+					AllOf: []*openapi.Schema{
+						&openapi.Schema{Ref: "#/components/schemas/Pet"},
+						&openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"name": &openapi.Schema{Type: "string"}}},
+					},
+				},
+				"Dog": &openapi.Schema{
+					AllOf: []*openapi.Schema{
+						&openapi.Schema{Ref: "#/components/schemas/Pet"},
+						&openapi.Schema{Type: "object", Required: []string{"bark"}, Properties: map[string]*openapi.Schema{"bark": &openapi.Schema{Type: "boolean"}}},
+					},
+				},
+				"MyPet": &openapi.Schema{
+					OneOf: []*openapi.Schema{
+						&openapi.Schema{Ref: "#/components/schemas/Cat"},
+						&openapi.Schema{Ref: "#/components/schemas/Dog"},
+					},
+					Discriminator: &openapi.Discriminator{
+						PropertyName: "petType",
+						Mapping:      map[string]string{"doggie": "#/components/schemas/Dog", "kitty": "Cat"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveDiscriminator(t *testing.T) {
+	doc := discriminatorDocument()
+	myPet := &openapi.Schema{Ref: "#/components/schemas/MyPet"}
+	pet := &openapi.Schema{Ref: "#/components/schemas/Pet"}
+	candidates := []struct {
+		label  string
+		schema *openapi.Schema
+		value  interface{}
+		expect string
+		err    error
+	}{
+		{"mapping ref", myPet, map[string]interface{}{"petType": "doggie"}, "Dog", nil},
+		{"mapping name", myPet, map[string]interface{}{"petType": "kitty"}, "Cat", nil},
+		{"implicit oneOf", myPet, map[string]interface{}{"petType": "Cat"}, "Cat", nil},
+		{"implicit component", pet, map[string]interface{}{"petType": "Dog"}, "Dog", nil},
+		{"unknown", myPet, map[string]interface{}{"petType": "Lizard"}, "", openapi.ErrUnknownDiscriminatorValue{Value: "Lizard"}},
+		{"missing property", myPet, map[string]interface{}{}, "", openapi.ErrValueInvalid{Reason: "missing required property petType"}},
+		{"not object", myPet, "Cat", "", openapi.ErrValueInvalid{Reason: "must be object"}},
+		{"no discriminator", &openapi.Schema{Ref: "#/components/schemas/Cat"}, map[string]interface{}{}, "", openapi.ErrRequired{Target: "schema.discriminator"}},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			s, err := openapi.ResolveDiscriminator(doc, c.schema, c.value)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %v, but %v", c.err, err)
+				return
+			}
+			if c.expect != "" && s != doc.Components.Schemas[c.expect] {
+				t.Errorf("schema should be %s", c.expect)
+			}
+		})
+	}
+}
+
+func TestDiscriminator_ValidateValue(t *testing.T) {
+	doc := discriminatorDocument()
+	myPet := &openapi.Schema{Ref: "#/components/schemas/MyPet"}
+	if err := myPet.ValidateValue(doc, map[string]interface{}{"petType": "doggie", "bark": true}); err != nil {
+		t.Error(err)
+	}
+	expect := openapi.ErrValueInvalid{Reason: "missing required property bark"}
+	if err := myPet.ValidateValue(doc, map[string]interface{}{"petType": "doggie"}); !reflect.DeepEqual(err, expect) {
+		t.Errorf("error should be %v, but %v", expect, err)
+	}
+}
+
+func TestDocument_ValidateDiscriminators(t *testing.T) {
+	doc := discriminatorDocument()
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	doc.Components.Schemas["MyPet"].Discriminator.Mapping["bird"] = "Bird"
+	expect := openapi.ErrDiscriminatorTargetNotFound{Target: "#/components/schemas/Bird"}
+	if err := doc.Validate(); !reflect.DeepEqual(err, expect) {
+		t.Errorf("error should be %v, but %v", expect, err)
+	}
+	delete(doc.Components.Schemas["MyPet"].Discriminator.Mapping, "bird")
+	doc.Components.Schemas["Pet"].Required = nil
+	if err := doc.Validate(); !reflect.DeepEqual(err, openapi.ErrDiscriminatorPropertyNotRequired{Property: "petType"}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -10,14 +10,14 @@ import (
 
 // Document represents a OpenAPI Specification document.
 type Document struct {
-	Version      string `yaml:"openapi"`
-	Info         *Info
-	Servers      []*Server
-	Paths        Paths
-	Components   *Components
-	Security     []*SecurityRequirement
-	Tags         []*Tag
-	ExternalDocs *ExternalDocumentation `yaml:"externalDocs"`
+	Version      string                 `yaml:"openapi,omitempty"`
+	Info         *Info                  `yaml:"info,omitempty"`
+	Servers      []*Server              `yaml:"servers,omitempty"`
+	Paths        Paths                  `yaml:"paths"`
+	Components   *Components            `yaml:"components,omitempty"`
+	Security     []*SecurityRequirement `yaml:"security,omitempty"`
+	Tags         []*Tag                 `yaml:"tags,omitempty"`
+	ExternalDocs *ExternalDocumentation `yaml:"externalDocs,omitempty"`
 }
 
 // Validate the values of spec.
@@ -28,7 +28,22 @@ func (doc Document) Validate() error {
 	if err := doc.validateOASVersion(); err != nil {
 		return err
 	}
-	return doc.validateFields()
+	if err := doc.validateFields(); err != nil {
+		return err
+	}
+	if err := doc.Paths.validatePathParameters(&doc); err != nil {
+		return err
+	}
+	if err := doc.validateLinks(); err != nil {
+		return err
+	}
+	if err := doc.validateDiscriminators(); err != nil {
+		return err
+	}
+	if err := doc.validateExamples(); err != nil {
+		return err
+	}
+	return doc.validateDefaults()
 }
 
 func (doc Document) validateOASVersion() error {
@@ -89,6 +104,60 @@ func (doc Document) validateFields() error {
 	return validateAll(validaters)
 }
 
+// validateLinks validates the links in the document, including the ones
+// in the callbacks, target existing operations.
+func (doc Document) validateLinks() error {
+	return doc.Visit(func(ptr string, node, parent interface{}) error {
+		if link, ok := node.(*Link); ok {
+			return link.validateTarget(&doc)
+		}
+		return nil
+	})
+}
+
+// validateDiscriminators validates the discriminators in the
+// component schemas.
+func (doc Document) validateDiscriminators() error {
+	if doc.Components == nil {
+		return nil
+	}
+	var names []string
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	visited := map[*Schema]bool{}
+	for _, name := range names {
+		if err := doc.validateSchemaDiscriminators(doc.Components.Schemas[name], visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (doc Document) validateSchemaDiscriminators(schema *Schema, visited map[*Schema]bool) error {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	if err := schema.validateDiscriminator(&doc); err != nil {
+		return err
+	}
+	children := []*Schema{schema.Not, schema.Items, schema.AdditionalProperties}
+	children = append(children, schema.AllOf...)
+	children = append(children, schema.OneOf...)
+	children = append(children, schema.AnyOf...)
+	for _, s := range schema.Properties {
+		children = append(children, s)
+	}
+	for _, s := range children {
+		if err := doc.validateSchemaDiscriminators(s, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type WalkFunc func(doc *Document, method, path string, pathItem *PathItem, op *Operation) error
 
 func (doc *Document) Walk(walkFn WalkFunc) error {
@@ -115,3 +184,30 @@ func (doc *Document) Walk(walkFn WalkFunc) error {
 	}
 	return nil
 }
+
+// validateDefaults validates the default values and the enum values of
+// the schemas in the document conform to the schemas.
+func (doc Document) validateDefaults() error {
+	return doc.Visit(func(ptr string, node, parent interface{}) error {
+		schema, ok := node.(*Schema)
+		if !ok || schema.Ref != "" {
+			return nil
+		}
+		if len(schema.Enum) > 0 {
+			// the enum values are validated without the enum itself
+			withoutEnum := *schema
+			withoutEnum.Enum = nil
+			for i, e := range schema.Enum {
+				if err := withoutEnum.ValidateValue(&doc, yamlToJSONValue(e)); err != nil {
+					return ErrDefaultInvalid{Pointer: ptr + "/enum/" + strconv.Itoa(i), Err: err}
+				}
+			}
+		}
+		if schema.Default != nil {
+			if err := schema.ValidateValue(&doc, yamlToJSONValue(schema.Default)); err != nil {
+				return ErrDefaultInvalid{Pointer: ptr + "/default", Err: err}
+			}
+		}
+		return nil
+	})
+}
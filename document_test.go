@@ -5,9 +5,10 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestDocument_Validate(t *testing.T) {
@@ -163,3 +164,248 @@ func TestDocument_Walk(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestDocument_ValidateExamples(t *testing.T) {
+	const header = `
+openapi: 3.0.2
+info:
+  title: examples
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+`
+	const components = `
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+  examples:
+    pet:
+      value:
+        name: kitty
+    noName:
+      value:
+        tag: cat
+`
+	candidates := []struct {
+		label     string
+		operation string
+		pointer   string
+	}{
+		{"valid", `
+      parameters:
+        - name: id
+          in: query
+          schema:
+            type: integer
+          example: 1
+      responses:
+        '200':
+          description: pet
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+              examples:
+                limit:
+                  value: 100
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              examples:
+                pet:
+                  $ref: '#/components/examples/pet'
+                external:
+                  externalValue: https://example.com/pet.json
+`, ""},
+		{"parameter", `
+      parameters:
+        - name: id
+          in: query
+          schema:
+            type: integer
+          example: one
+      responses: {}
+`, "/paths/~1pets/get/parameters/0/example"},
+		{"headerExamples", `
+      responses:
+        '200':
+          description: pet
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+              examples:
+                limit:
+                  value: unlimited
+`, "/paths/~1pets/get/responses/200/headers/X-Rate-Limit/examples/limit/value"},
+		{"mediaType", `
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              example:
+                tag: cat
+`, "/paths/~1pets/get/responses/200/content/application~1json/example"},
+		{"referredExample", `
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              examples:
+                noName:
+                  $ref: '#/components/examples/noName'
+`, "/paths/~1pets/get/responses/200/content/application~1json/examples/noName/value"},
+		{"schema", `
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                type: string
+                maxLength: 3
+                example: kitty
+`, "/paths/~1pets/get/responses/200/content/application~1json/schema/example"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			doc, err := openapi.Load([]byte(header + c.operation + components))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = doc.Validate()
+			if c.pointer == "" {
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			invalid, ok := err.(openapi.ErrExampleInvalid)
+			if !ok {
+				t.Fatalf("error should be ErrExampleInvalid, but %v", err)
+			}
+			if invalid.Pointer != c.pointer {
+				t.Errorf("pointer: %s != %s", invalid.Pointer, c.pointer)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateDefaults(t *testing.T) {
+	const header = `
+openapi: 3.0.2
+info:
+  title: defaults
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Schema:
+`
+	candidates := []struct {
+		label   string
+		schema  string
+		pointer string
+	}{
+		{"valid", `
+      type: object
+      default: {size: 1}
+      properties:
+        size:
+          type: integer
+          enum: [1, 2, 3]
+          default: 2
+`, ""},
+		{"defaultType", `
+      type: integer
+      default: one
+`, "/components/schemas/Schema/default"},
+		{"defaultNotInEnum", `
+      type: integer
+      enum: [1, 2]
+      default: 3
+`, "/components/schemas/Schema/default"},
+		{"enumType", `
+      type: integer
+      enum: [1, two]
+`, "/components/schemas/Schema/enum/1"},
+		{"nestedDefault", `
+      type: object
+      properties:
+        size:
+          type: integer
+          minimum: 1
+          default: 0
+`, "/components/schemas/Schema/properties/size/default"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			doc, err := openapi.Load([]byte(header + c.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = doc.Validate()
+			if c.pointer == "" {
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			invalid, ok := err.(openapi.ErrDefaultInvalid)
+			if !ok {
+				t.Fatalf("error should be ErrDefaultInvalid, but %v", err)
+			}
+			if invalid.Pointer != c.pointer {
+				t.Errorf("pointer: %s != %s", invalid.Pointer, c.pointer)
+			}
+		})
+	}
+}
+
+func TestDocument_MarshalJSON_Precision(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: precision
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    constant:
+      type: number
+      default: 3.14159265
+      enum: [3.14159265, 2.718281828]
+      example: 1.41421356
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dereferenced, err := doc.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := dereferenced.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{`"default":3.14159265`, `"enum":[3.14159265,2.718281828]`, `"example":1.41421356`} {
+		t.Run(strconv.Itoa(i)+"/"+want, func(t *testing.T) {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("%s is not in: %s", want, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import "strings"
+
+// codebeat:disable[TOO_MANY_IVARS]
+
+// EffectiveOperation is the view of an operation merged with the data
+// of its path item and the document. The references in the parameters,
+// the request body and the responses are resolved.
+type EffectiveOperation struct {
+	Path   string
+	Method string
+	// Operation is the original operation object.
+	Operation *Operation
+
+	// Parameters are the parameters of the path item and the operation.
+	// The parameters of the operation override the ones of the path item
+	// which have the same name and location.
+	Parameters  []*Parameter
+	RequestBody *RequestBody
+	Responses   Responses
+	// Servers are the servers of the operation, the path item or the
+	// document in this order of precedence.
+	Servers []*Server
+	// Security is the security requirements of the operation, or the
+	// document if the operation does not declare them. An empty slice
+	// means the operation requires no security.
+	Security []*SecurityRequirement
+}
+
+// EffectiveOperation returns the effective view of the operation for the
+// method of the path. The path is the key of the paths object, like "/pets/{id}".
+func (doc *Document) EffectiveOperation(method, path string) (*EffectiveOperation, error) {
+	pathItem, ok := doc.Paths[path]
+	if !ok || pathItem == nil {
+		return nil, ErrOperationNotFound
+	}
+	op := pathItem.GetOperationByMethod(method)
+	if op == nil {
+		return nil, ErrOperationNotFound
+	}
+	return doc.effectiveOperation(strings.ToUpper(method), path, pathItem, op)
+}
+
+// EffectiveOperations returns the effective views of all the operations in
+// the document, in order of the paths and the methods.
+func (doc *Document) EffectiveOperations() ([]*EffectiveOperation, error) {
+	var ops []*EffectiveOperation
+	err := doc.Walk(func(doc *Document, method, path string, pathItem *PathItem, op *Operation) error {
+		eop, err := doc.effectiveOperation(method, path, pathItem, op)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, eop)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func (doc *Document) effectiveOperation(method, path string, pathItem *PathItem, op *Operation) (*EffectiveOperation, error) {
+	params, err := operationParameters(doc, pathItem, op)
+	if err != nil {
+		return nil, err
+	}
+	eop := &EffectiveOperation{
+		Path:        path,
+		Method:      method,
+		Operation:   op,
+		Parameters:  params,
+		RequestBody: op.RequestBody,
+		Servers:     effectiveServers(doc, pathItem, op),
+		Security:    doc.Security,
+	}
+	if op.Security != nil {
+		eop.Security = *op.Security
+	}
+	if eop.RequestBody != nil && eop.RequestBody.Ref != "" {
+		requestBody, err := ResolveRequestBody(doc, eop.RequestBody.Ref)
+		if err != nil {
+			return nil, err
+		}
+		eop.RequestBody = requestBody
+	}
+	if op.Responses != nil {
+		eop.Responses = make(Responses, len(op.Responses))
+		for status, response := range op.Responses {
+			if response != nil && response.Ref != "" {
+				response, err = ResolveResponse(doc, response.Ref)
+				if err != nil {
+					return nil, err
+				}
+			}
+			eop.Responses[status] = response
+		}
+	}
+	return eop, nil
+}
+
+// effectiveServers returns the servers of the operation, the path item or
+// the document in this order of precedence.
+func effectiveServers(doc *Document, pathItem *PathItem, op *Operation) []*Server {
+	if op != nil && len(op.Servers) > 0 {
+		return op.Servers
+	}
+	if pathItem != nil && len(pathItem.Servers) > 0 {
+		return pathItem.Servers
+	}
+	return doc.Servers
+}
@@ -0,0 +1,174 @@
+package openapi_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const effectiveOperationSpec = `
+openapi: 3.0.2
+info:
+  title: effective
+  version: 1.0.0
+servers:
+  - url: https://example.com
+security:
+  - api_key: []
+paths:
+  /pets/{id}:
+    servers:
+      - url: https://pets.example.com
+    parameters:
+      - name: id
+        in: path
+        required: true
+        description: path level
+        schema:
+          type: string
+      - $ref: '#/components/parameters/Limit'
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          description: operation level
+          schema:
+            type: integer
+      responses:
+        '200':
+          $ref: '#/components/responses/Pet'
+    put:
+      operationId: putPet
+      servers:
+        - url: https://write.example.com
+      security: []
+      requestBody:
+        $ref: '#/components/requestBodies/Pet'
+      responses:
+        '204':
+          description: updated
+  /health:
+    get:
+      operationId: health
+      responses:
+        '200':
+          description: ok
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  responses:
+    Pet:
+      description: a pet
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema:
+            type: object
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-KEY
+      in: header
+`
+
+func TestDocument_EffectiveOperation(t *testing.T) {
+	doc, err := openapi.Load([]byte(effectiveOperationSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	get, err := doc.EffectiveOperation("get", "/pets/{id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if get.Method != http.MethodGet || get.Operation != doc.Paths["/pets/{id}"].Get {
+		t.Errorf("unexpected operation: %s %+v", get.Method, get.Operation)
+	}
+	if len(get.Parameters) != 2 {
+		t.Fatalf("unexpected parameters: %+v", get.Parameters)
+	}
+	if get.Parameters[0].Description != "operation level" || get.Parameters[1].Name != "limit" {
+		t.Errorf("unexpected parameters: %+v, %+v", get.Parameters[0], get.Parameters[1])
+	}
+	if get.Responses["200"].Description != "a pet" {
+		t.Errorf("response is not resolved: %+v", get.Responses["200"])
+	}
+	if get.Servers[0].URL != "https://pets.example.com" {
+		t.Errorf("unexpected server: %s", get.Servers[0].URL)
+	}
+	if len(get.Security) != 1 || get.Security[0].Names()[0] != "api_key" {
+		t.Errorf("unexpected security: %+v", get.Security)
+	}
+
+	put, err := doc.EffectiveOperation(http.MethodPut, "/pets/{id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if put.Servers[0].URL != "https://write.example.com" {
+		t.Errorf("unexpected server: %s", put.Servers[0].URL)
+	}
+	if put.Security == nil || len(put.Security) != 0 {
+		t.Errorf("security should be overridden with empty: %+v", put.Security)
+	}
+	if put.RequestBody == nil || put.RequestBody.Content["application/json"] == nil {
+		t.Errorf("request body is not resolved: %+v", put.RequestBody)
+	}
+	// original objects are not modified
+	if doc.Paths["/pets/{id}"].Put.RequestBody.Ref == "" {
+		t.Error("original request body should be kept")
+	}
+}
+
+func TestDocument_EffectiveOperation_NotFound(t *testing.T) {
+	doc, err := openapi.Load([]byte(effectiveOperationSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := []struct {
+		label  string
+		method string
+		path   string
+	}{
+		{"unknownPath", http.MethodGet, "/unknown"},
+		{"unknownMethod", http.MethodDelete, "/health"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			_, err := doc.EffectiveOperation(c.method, c.path)
+			if err != openapi.ErrOperationNotFound {
+				t.Errorf("%v != %v", err, openapi.ErrOperationNotFound)
+			}
+		})
+	}
+}
+
+func TestDocument_EffectiveOperations(t *testing.T) {
+	doc, err := openapi.Load([]byte(effectiveOperationSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := doc.EffectiveOperations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"health", "getPet", "putPet"}
+	if len(ops) != len(expected) {
+		t.Fatalf("%d != %d", len(ops), len(expected))
+	}
+	for i, op := range ops {
+		if op.Operation.OperationID != expected[i] {
+			t.Errorf("%s != %s", op.Operation.OperationID, expected[i])
+		}
+	}
+	if ops[0].Servers[0].URL != "https://example.com" {
+		t.Errorf("unexpected server: %s", ops[0].Servers[0].URL)
+	}
+}
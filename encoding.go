@@ -4,11 +4,11 @@ package openapi
 
 // Encoding Object
 type Encoding struct {
-	ContentType   string `yaml:"contentType"`
-	Headers       map[string]*Header
-	Style         string
-	Explode       bool
-	AllowReserved bool `yaml:"allowReserved"`
+	ContentType   string             `yaml:"contentType,omitempty"`
+	Headers       map[string]*Header `yaml:"headers,omitempty"`
+	Style         string             `yaml:"style,omitempty"`
+	Explode       *bool              `yaml:"explode,omitempty"`
+	AllowReserved bool               `yaml:"allowReserved,omitempty"`
 }
 
 // Validate the values of Encoding object.
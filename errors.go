@@ -59,6 +59,30 @@ const (
 	// ErrMissingRootDocument is returned when validating securityRequirement
 	// object but root document is not set.
 	ErrMissingRootDocument errString = "missing root document for security requirement"
+	// ErrMissingReferenceRoot is returned when resolving a reference
+	// but root document is not given.
+	ErrMissingReferenceRoot errString = "missing root document to resolve reference"
+	// ErrCallbackNotFound is returned when the callback is not declared
+	// in the operation.
+	ErrCallbackNotFound errString = "callback is not found"
+	// ErrOperationNotFound is returned when the operation specified by
+	// operationId or operationRef is not found in the document.
+	ErrOperationNotFound errString = "operation is not found"
+	// ErrLinkNotFound is returned when the link is not declared in
+	// the response.
+	ErrLinkNotFound errString = "link is not found"
+	// ErrExternalOperationRef is returned when following a link whose
+	// operationRef refers an operation in another document.
+	ErrExternalOperationRef errString = "operationRef to another document is not supported"
+	// ErrUnsupportedMediaType is returned when the value cannot be
+	// encoded for the media type.
+	ErrUnsupportedMediaType errString = "the media type is not supported"
+	// ErrStyleNotApplicable is returned when the style of parameter
+	// cannot be applied to the type of the value.
+	ErrStyleNotApplicable errString = "the style is not applicable to the value"
+	// ErrMultipleOfNotPositive is returned when schema.multipleOf is
+	// not greater than 0.
+	ErrMultipleOfNotPositive errString = "schema.multipleOf must be greater than 0"
 )
 
 type errTooManyContentEntry struct {
@@ -78,6 +102,23 @@ var (
 	ErrTooManyParameterContent = errTooManyContentEntry{target: "parameter"}
 )
 
+type errMutuallyExclusive struct {
+	fields [2]string
+}
+
+func (mee errMutuallyExclusive) Error() string {
+	return fmt.Sprintf("%s and %s are mutually exclusive", mee.fields[0], mee.fields[1])
+}
+
+var (
+	// ErrExampleAndExamples is returned when both example and examples
+	// are set to the parameter, the header or the media type.
+	ErrExampleAndExamples = errMutuallyExclusive{fields: [2]string{"example", "examples"}}
+	// ErrValueAndExternalValue is returned when both value and
+	// externalValue are set to the example.
+	ErrValueAndExternalValue = errMutuallyExclusive{fields: [2]string{"value", "externalValue"}}
+)
+
 type errDuplicated struct {
 	target string
 }
@@ -107,6 +148,178 @@ func (snde ErrNotDeclared) Error() string {
 	return fmt.Sprintf("%s is not declared in components.securitySchemes", snde.Name)
 }
 
+// ErrRuntimeExprNotFound is returned when the value referred by
+// the runtime expression is not found in the request or the response.
+type ErrRuntimeExprNotFound struct {
+	Expr string
+}
+
+func (enf ErrRuntimeExprNotFound) Error() string {
+	return fmt.Sprintf("value of %s is not found", enf.Expr)
+}
+
+// ErrParameterNotDeclared is returned when the parameter name in
+// link.parameters is not declared in the target operation.
+type ErrParameterNotDeclared struct {
+	Name string
+}
+
+func (pnde ErrParameterNotDeclared) Error() string {
+	return fmt.Sprintf("parameter %s is not declared in the target operation", pnde.Name)
+}
+
+// ErrPathParameterNotDeclared is returned when the template segment
+// in the path has no corresponding path parameter.
+type ErrPathParameterNotDeclared struct {
+	Path string
+	Name string
+}
+
+func (ppnde ErrPathParameterNotDeclared) Error() string {
+	return fmt.Sprintf("path parameter %s in %s is not declared", ppnde.Name, ppnde.Path)
+}
+
+// ErrPathParameterNotInTemplate is returned when the path parameter
+// is declared but the path has no corresponding template segment.
+type ErrPathParameterNotInTemplate struct {
+	Path string
+	Name string
+}
+
+func (ppnite ErrPathParameterNotInTemplate) Error() string {
+	return fmt.Sprintf("path parameter %s is not in the path template %s", ppnite.Name, ppnite.Path)
+}
+
+// ErrUnknownDiscriminatorValue is returned when no schema is
+// selected by the discriminator property value.
+type ErrUnknownDiscriminatorValue struct {
+	Value string
+}
+
+func (udve ErrUnknownDiscriminatorValue) Error() string {
+	return fmt.Sprintf("no schema matches discriminator value %s", udve.Value)
+}
+
+// ErrDiscriminatorTargetNotFound is returned when the schema
+// in discriminator.mapping is not found.
+type ErrDiscriminatorTargetNotFound struct {
+	Target string
+}
+
+func (dtnfe ErrDiscriminatorTargetNotFound) Error() string {
+	return fmt.Sprintf("discriminator target %s is not found", dtnfe.Target)
+}
+
+// ErrDiscriminatorPropertyNotRequired is returned when a target schema
+// of the discriminator does not declare the property as required.
+type ErrDiscriminatorPropertyNotRequired struct {
+	Property string
+}
+
+func (dpnre ErrDiscriminatorPropertyNotRequired) Error() string {
+	return fmt.Sprintf("discriminator property %s must be required in the target schemas", dpnre.Property)
+}
+
+// ErrTypeNotSupported is returned when the Go type cannot be
+// converted to a schema.
+type ErrTypeNotSupported struct {
+	Type string
+}
+
+func (tnse ErrTypeNotSupported) Error() string {
+	return fmt.Sprintf("type %s is not supported", tnse.Type)
+}
+
+// ErrComponentNotFound is returned when the component is not
+// declared in the components object.
+type ErrComponentNotFound struct {
+	Kind string
+	Name string
+}
+
+func (cnfe ErrComponentNotFound) Error() string {
+	return fmt.Sprintf("%s is not found in components.%s", cnfe.Name, cnfe.Kind)
+}
+
+// ErrReferenceCycle is returned when the reference refers itself through
+// the other references, like A to B and B to A.
+type ErrReferenceCycle struct {
+	Ref string
+}
+
+func (rce ErrReferenceCycle) Error() string {
+	return fmt.Sprintf("reference %s refers itself", rce.Ref)
+}
+
+// ErrComponentExists is returned when the component is already
+// declared in the components object.
+type ErrComponentExists struct {
+	Kind string
+	Name string
+}
+
+func (cee ErrComponentExists) Error() string {
+	return fmt.Sprintf("%s already exists in components.%s", cee.Name, cee.Kind)
+}
+
+// ErrMergeConflict is returned when the documents cannot be merged.
+// Conflicts are the locations of the conflicts, like
+// "components.schemas.Pet" or "operationId listPets".
+type ErrMergeConflict struct {
+	Conflicts []string
+}
+
+func (mce ErrMergeConflict) Error() string {
+	return fmt.Sprintf("merge conflicts: %s", strings.Join(mce.Conflicts, ", "))
+}
+
+// ErrPatchFailed is returned when an operation of JSON Patch cannot
+// be applied to the document.
+type ErrPatchFailed struct {
+	Index  int
+	Op     string
+	Path   string
+	Reason string
+}
+
+func (pfe ErrPatchFailed) Error() string {
+	return fmt.Sprintf("patch operation %d (%s %s) failed: %s", pfe.Index, pfe.Op, pfe.Path, pfe.Reason)
+}
+
+// ErrExampleInvalid is returned when the example does not conform to
+// its schema. Pointer is the JSON pointer to the example in the document.
+type ErrExampleInvalid struct {
+	Pointer string
+	Err     error
+}
+
+func (eie ErrExampleInvalid) Error() string {
+	return fmt.Sprintf("example at %s does not conform to the schema: %s", eie.Pointer, eie.Err)
+}
+
+// ErrDefaultInvalid is returned when the default value or an enum value
+// of the schema does not conform to the schema. Pointer is the JSON
+// pointer to the value in the document.
+type ErrDefaultInvalid struct {
+	Pointer string
+	Err     error
+}
+
+func (die ErrDefaultInvalid) Error() string {
+	return fmt.Sprintf("value at %s does not conform to the schema: %s", die.Pointer, die.Err)
+}
+
+// ErrUnknownField is returned when the object has a field which is neither
+// defined in the specification nor a specification extension.
+type ErrUnknownField struct {
+	Object string
+	Name   string
+}
+
+func (ufe ErrUnknownField) Error() string {
+	return fmt.Sprintf("unknown field %s in %s: specification extensions must start with x-", ufe.Name, ufe.Object)
+}
+
 // ErrMustEmpty returned when the securityRequirement is not
 // empty but must be empty.
 type ErrMustEmpty struct {
@@ -4,10 +4,88 @@ package openapi
 
 // Example Object
 type Example struct {
-	Summary       string
-	Description   string
-	Value         interface{}
-	ExternalValue interface{} `yaml:"externalValue"`
+	Summary       string      `yaml:"summary,omitempty"`
+	Description   string      `yaml:"description,omitempty"`
+	Value         interface{} `yaml:"value,omitempty"`
+	ExternalValue interface{} `yaml:"externalValue,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
+}
+
+// Validate the values of Example object.
+// The conformance of the value to the schema is checked by
+// Document.Validate, as the schema is not in the example.
+func (example Example) Validate() error {
+	if example.Value != nil && example.ExternalValue != nil {
+		return ErrValueAndExternalValue
+	}
+	return nil
+}
+
+// validateExamples validates the examples in the document conform to
+// their schemas: the examples of the schemas, the parameters, the headers
+// and the media types, and the values of their Example objects.
+// The external values are not fetched.
+func (doc Document) validateExamples() error {
+	return doc.Visit(func(ptr string, node, parent interface{}) error {
+		switch node := node.(type) {
+		case *Schema:
+			if node.Ref == "" && node.Example != nil {
+				return doc.validateExample(node, node.Example, ptr+"/example")
+			}
+		case *Parameter:
+			if node.Ref == "" && node.Example != nil {
+				return doc.validateExample(node.Schema, node.Example, ptr+"/example")
+			}
+		case *Header:
+			if node.Ref == "" && node.Example != nil {
+				return doc.validateExample(node.Schema, node.Example, ptr+"/example")
+			}
+		case *MediaType:
+			if node.Example != nil {
+				return doc.validateExample(node.Schema, node.Example, ptr+"/example")
+			}
+		case *Example:
+			return doc.validateExampleObject(node, parent, ptr)
+		}
+		return nil
+	})
+}
+
+// validateExampleObject validates the value of the example object
+// conforms to the schema of the parent object.
+func (doc Document) validateExampleObject(example *Example, parent interface{}, ptr string) error {
+	var schema *Schema
+	switch parent := parent.(type) {
+	case *Parameter:
+		schema = parent.Schema
+	case *Header:
+		schema = parent.Schema
+	case *MediaType:
+		schema = parent.Schema
+	default:
+		// the examples in the components have no schema
+		return nil
+	}
+	if example.Ref != "" {
+		resolved, err := ResolveExample(&doc, example.Ref)
+		if err != nil {
+			return err
+		}
+		example = resolved
+	}
+	if example == nil || example.Value == nil {
+		return nil
+	}
+	return doc.validateExample(schema, example.Value, ptr+"/value")
+}
+
+func (doc Document) validateExample(schema *Schema, value interface{}, ptr string) error {
+	if schema == nil {
+		return nil
+	}
+	if err := schema.ValidateValue(&doc, yamlToJSONValue(value)); err != nil {
+		return ErrExampleInvalid{Pointer: ptr, Err: err}
+	}
+	return nil
 }
@@ -0,0 +1,17 @@
+package openapi_test
+
+import (
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestExample_Validate(t *testing.T) {
+	candidates := []candidate{
+		{"empty", openapi.Example{}, nil},
+		{"value", openapi.Example{Value: "foo"}, nil},
+		{"externalValue", openapi.Example{ExternalValue: exampleCom}, nil},
+		{"valueAndExternalValue", openapi.Example{Value: "foo", ExternalValue: exampleCom}, openapi.ErrValueAndExternalValue},
+	}
+	testValidater(t, candidates)
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import "strings"
+
+// OperationFilter reports whether the operation is kept by Document.Filter.
+// The method is in upper case.
+type OperationFilter func(path, method string, op *Operation) bool
+
+// TagFilter keeps the operations which have any of the tags.
+func TagFilter(tags ...string) OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		for _, tag := range op.Tags {
+			if containsString(tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PathPrefixFilter keeps the operations whose path starts with the prefix.
+func PathPrefixFilter(prefix string) OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		return strings.HasPrefix(path, prefix)
+	}
+}
+
+// ExtensionFilter keeps the operations which have the specification
+// extension, like x-public, whose value is not false nor null.
+func ExtensionFilter(name string) OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		v, ok := op.SpecificationExtensions[name]
+		if !ok || v == nil {
+			return false
+		}
+		if b, ok := v.(bool); ok {
+			return b
+		}
+		return true
+	}
+}
+
+// NotDeprecatedFilter keeps the operations which are not deprecated.
+func NotDeprecatedFilter() OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		return !op.Deprecated
+	}
+}
+
+// AndFilter keeps the operations which are kept by all of the filters.
+func AndFilter(filters ...OperationFilter) OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		for _, filter := range filters {
+			if !filter(path, method, op) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// OrFilter keeps the operations which are kept by any of the filters.
+func OrFilter(filters ...OperationFilter) OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		for _, filter := range filters {
+			if filter(path, method, op) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NotFilter keeps the operations which are not kept by the filter.
+func NotFilter(filter OperationFilter) OperationFilter {
+	return func(path, method string, op *Operation) bool {
+		return !filter(path, method, op)
+	}
+}
+
+// Filter returns a new document which has only the operations kept by
+// the filter. The path items which have no operations are removed.
+// Then the components which are no longer referenced transitively from
+// the paths and the security requirements, and the tags which are not
+// used by the remaining operations are removed.
+// The document itself is not modified.
+func (doc *Document) Filter(filter OperationFilter) (*Document, error) {
+	filtered := doc.clone()
+	for _, path := range sortedMapKeys(filtered.Paths) {
+		pathItem := filtered.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range methods {
+			op := pathItem.GetOperationByMethod(method)
+			if op != nil && !filter(path, method, op) {
+				pathItem.setOperation(method, nil)
+			}
+		}
+		if len(pathItem.Operations()) == 0 {
+			delete(filtered.Paths, path)
+		}
+	}
+
+	reachable, err := filtered.reachableComponents()
+	if err != nil {
+		return nil, err
+	}
+	filtered.pruneComponents(reachable)
+	filtered.pruneTags()
+	return filtered, nil
+}
+
+// pruneTags removes the tags which are not used by any operation.
+func (doc *Document) pruneTags() {
+	used := map[string]bool{}
+	for _, pathItem := range doc.Paths {
+		if pathItem == nil {
+			continue
+		}
+		for _, op := range pathItem.Operations() {
+			for _, tag := range op.Tags {
+				used[tag] = true
+			}
+		}
+	}
+	var tags []*Tag
+	for _, tag := range doc.Tags {
+		if tag != nil && used[tag.Name] {
+			tags = append(tags, tag)
+		}
+	}
+	doc.Tags = tags
+}
@@ -0,0 +1,170 @@
+package openapi_test
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const filterSpec = `
+openapi: 3.0.2
+info:
+  title: filter
+  version: 1.0.0
+tags:
+  - name: pets
+  - name: admin
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      x-public: true
+      security: []
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+  /pets/{id}:
+    parameters:
+      - $ref: '#/components/parameters/ID'
+    get:
+      operationId: getPet
+      tags: [pets]
+      deprecated: true
+      x-public: true
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+  /admin/users:
+    get:
+      operationId: listUsers
+      tags: [admin]
+      x-public: false
+      security:
+        - admin_auth: []
+      responses:
+        '200':
+          description: users
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  parameters:
+    ID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+    User:
+      type: object
+  securitySchemes:
+    admin_auth:
+      type: http
+      scheme: basic
+`
+
+func TestDocument_Filter(t *testing.T) {
+	doc, err := openapi.Load([]byte(filterSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := []struct {
+		label      string
+		filter     openapi.OperationFilter
+		operations []string
+		schemas    []string
+		tags       []string
+		schemes    []string
+	}{
+		{"tag", openapi.TagFilter("pets"), []string{"listPets", "getPet"}, []string{"Owner", "Pet"}, []string{"pets"}, nil},
+		{"pathPrefix", openapi.PathPrefixFilter("/admin"), []string{"listUsers"}, []string{"User"}, []string{"admin"}, []string{"admin_auth"}},
+		{"extension", openapi.ExtensionFilter("x-public"), []string{"listPets", "getPet"}, []string{"Owner", "Pet"}, []string{"pets"}, nil},
+		{"notDeprecated", openapi.AndFilter(openapi.TagFilter("pets"), openapi.NotDeprecatedFilter()), []string{"listPets"}, []string{"Owner", "Pet"}, []string{"pets"}, nil},
+		{"not", openapi.NotFilter(openapi.OrFilter(openapi.TagFilter("pets"), openapi.TagFilter("admin"))), nil, nil, nil, nil},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			filtered, err := doc.Filter(c.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ops []string
+			filtered.Walk(func(doc *openapi.Document, method, path string, pathItem *openapi.PathItem, op *openapi.Operation) error {
+				ops = append(ops, op.OperationID)
+				return nil
+			})
+			if !reflect.DeepEqual(ops, c.operations) {
+				t.Errorf("operations: %v != %v", ops, c.operations)
+			}
+			var schemas, schemes []string
+			if filtered.Components != nil {
+				schemas = sortedKeysOf(filtered.Components.Schemas)
+				schemes = sortedKeysOf(filtered.Components.SecuritySchemes)
+			}
+			if !reflect.DeepEqual(schemas, c.schemas) {
+				t.Errorf("schemas: %v != %v", schemas, c.schemas)
+			}
+			if !reflect.DeepEqual(schemes, c.schemes) {
+				t.Errorf("security schemes: %v != %v", schemes, c.schemes)
+			}
+			var tags []string
+			for _, tag := range filtered.Tags {
+				tags = append(tags, tag.Name)
+			}
+			if !reflect.DeepEqual(tags, c.tags) {
+				t.Errorf("tags: %v != %v", tags, c.tags)
+			}
+			if err := filtered.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	if len(doc.Paths) != 3 || len(doc.Components.Schemas) != 3 {
+		t.Error("original document should not be modified")
+	}
+
+	filtered, err := doc.Filter(openapi.TagFilter("pets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if security := filtered.Paths["/pets"].Get.Security; security == nil || len(*security) != 0 {
+		t.Errorf("the empty security should be kept: %+v", security)
+	}
+}
+
+// sortedKeysOf returns the sorted keys of the map which has string keys.
+func sortedKeysOf(m interface{}) []string {
+	rv := reflect.ValueOf(m)
+	if rv.Len() == 0 {
+		return nil
+	}
+	var keys []string
+	for _, k := range rv.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
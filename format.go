@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"encoding/base64"
+	"math"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format is a format of schema values, like "date-time" or "int32",
+// which is used by Schema.ValidateValue and Schema.ExampleValue.
+type Format struct {
+	// Validate reports whether the value conforms to the format. The value
+	// is a decoded JSON value, and the values of other types than the
+	// format applies to should be reported as valid.
+	Validate func(value interface{}) bool
+	// Example is the example of the string format. It is empty if the
+	// format is not for strings.
+	Example string
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Format{
+		"date":      stringFormat(isDate, "2019-01-01"),
+		"date-time": stringFormat(isDateTime, "2019-01-01T00:00:00Z"),
+		"email":     stringFormat(isEmail, "user@example.com"),
+		"uuid":      stringFormat(uuidRegexp.MatchString, "00000000-0000-0000-0000-000000000000"),
+		"uri":       stringFormat(isURI, "https://example.com"),
+		"ipv4":      stringFormat(isIPv4, "192.0.2.1"),
+		"ipv6":      stringFormat(isIPv6, "2001:db8::1"),
+		"hostname":  stringFormat(isHostname, "example.com"),
+		"byte":      stringFormat(isBase64, "c3RyaW5n"),
+		"binary":    stringFormat(nil, ""),
+		"password":  stringFormat(nil, "password"),
+		"int32":     integerFormat(32),
+		"int64":     integerFormat(64),
+		"float":     numberFormat(isFloat32),
+		"double":    numberFormat(isFloat64),
+	}
+)
+
+// RegisterFormat registers the format with the name, replacing the
+// format of the same name including the built-in one.
+// The built-in formats are date, date-time, email, uuid, uri, ipv4, ipv6,
+// hostname, byte, binary and password for strings, and int32, int64,
+// float and double for numbers.
+func RegisterFormat(name string, format Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[name] = format
+}
+
+// LookupFormat returns the format registered with the name.
+func LookupFormat(name string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	format, ok := formats[name]
+	return format, ok
+}
+
+// stringFormat returns the format which validates strings with valid.
+// All strings are valid if valid is nil.
+func stringFormat(valid func(string) bool, example string) Format {
+	return Format{
+		Validate: func(value interface{}) bool {
+			s, ok := value.(string)
+			return !ok || valid == nil || valid(s)
+		},
+		Example: example,
+	}
+}
+
+// numberFormat returns the format which validates numbers with valid.
+func numberFormat(valid func(float64) bool) Format {
+	return Format{
+		Validate: func(value interface{}) bool {
+			f, ok := toFloat(value)
+			return !ok || valid(f)
+		},
+	}
+}
+
+// integerFormat returns the format which validates that the numbers are
+// integers of the bit size. The numbers are compared exactly, not as
+// float64, so 9223372036854775808 is not an int64.
+func integerFormat(bitSize uint) Format {
+	max := new(big.Int).Lsh(big.NewInt(1), bitSize-1)
+	min := new(big.Int).Neg(max)
+	max.Sub(max, big.NewInt(1))
+	return Format{
+		Validate: func(value interface{}) bool {
+			n, ok := toInt(value)
+			if !ok {
+				return true
+			}
+			return n != nil && min.Cmp(n) <= 0 && n.Cmp(max) <= 0
+		},
+	}
+}
+
+// toInt returns the numeric value as an integer. It returns nil and true
+// if the value is a number but not an integer.
+func toInt(value interface{}) (*big.Int, bool) {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), true
+	}
+	r, ok := toRat(value)
+	if !ok {
+		return nil, false
+	}
+	if !r.IsInt() {
+		return nil, true
+	}
+	return r.Num(), true
+}
+
+var (
+	uuidRegexp     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnameRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+func isDateTime(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func isURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
+
+func isIPv4(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && !strings.Contains(s, ":")
+}
+
+func isIPv6(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && strings.Contains(s, ":")
+}
+
+func isHostname(s string) bool {
+	return len(s) <= 253 && hostnameRegexp.MatchString(s)
+}
+
+func isBase64(s string) bool {
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
+
+func isFloat32(f float64) bool {
+	return math.Abs(f) <= math.MaxFloat32
+}
+
+func isFloat64(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
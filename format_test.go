@@ -0,0 +1,93 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestFormat(t *testing.T) {
+	candidates := []struct {
+		label  string
+		format string
+		value  interface{}
+		valid  bool
+	}{
+		{"date", "date", "2019-01-31", true},
+		{"invalidDate", "date", "2019-02-31", false},
+		{"dateTime", "date-time", "2019-01-01T00:00:00.123+09:00", true},
+		{"invalidDateTime", "date-time", "2019-01-01 00:00:00", false},
+		{"email", "email", "user@example.com", true},
+		{"emailWithName", "email", "User <user@example.com>", false},
+		{"uuid", "uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"invalidUUID", "uuid", "123e4567e89b12d3a456426614174000", false},
+		{"uri", "uri", "https://example.com/path?q=1", true},
+		{"relativeURI", "uri", "/path", false},
+		{"ipv4", "ipv4", "192.0.2.1", true},
+		{"ipv6AsIPv4", "ipv4", "2001:db8::1", false},
+		{"ipv6", "ipv6", "2001:db8::1", true},
+		{"ipv4AsIPv6", "ipv6", "192.0.2.1", false},
+		{"hostname", "hostname", "api.example.com", true},
+		{"invalidHostname", "hostname", "-example.com", false},
+		{"byte", "byte", "c3RyaW5n", true},
+		{"invalidByte", "byte", "string!", false},
+		{"binary", "binary", "\x00\x01", true},
+		{"int32", "int32", float64(2147483647), true},
+		{"int32Overflow", "int32", float64(2147483648), false},
+		{"int64Fraction", "int64", 1.5, false},
+		{"int64", "int64", json.Number("9223372036854775807"), true},
+		{"int64Overflow", "int64", json.Number("9223372036854775808"), false},
+		{"int64Underflow", "int64", json.Number("-9223372036854775809"), false},
+		{"int64FloatOverflow", "int64", float64(9223372036854775807), false},
+		{"int64Uint", "int64", uint64(9223372036854775808), false},
+		{"int64Exponent", "int64", json.Number("1e3"), true},
+		{"float", "float", 1.5, true},
+		{"floatOverflow", "float", 1e39, false},
+		{"double", "double", 1e39, true},
+		{"otherType", "date", float64(1), true},
+		{"unknown", "unknown", "anything", true},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			schema := &openapi.Schema{Format: c.format}
+			err := schema.ValidateValue(nil, c.value)
+			if c.valid && err != nil {
+				t.Error(err)
+			}
+			if !c.valid && err != (openapi.ErrValueInvalid{Reason: "must be in format " + c.format}) {
+				t.Errorf("error should be invalid format, but %v", err)
+			}
+		})
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	openapi.RegisterFormat("x-upper", openapi.Format{
+		Validate: func(value interface{}) bool {
+			s, ok := value.(string)
+			return !ok || s == strings.ToUpper(s)
+		},
+		Example: "UPPER",
+	})
+	format, ok := openapi.LookupFormat("x-upper")
+	if !ok || format.Example != "UPPER" {
+		t.Fatalf("format is not registered: %v", format)
+	}
+	schema := &openapi.Schema{Type: "string", Format: "x-upper"}
+	if err := schema.ValidateValue(nil, "FOO"); err != nil {
+		t.Error(err)
+	}
+	if err := schema.ValidateValue(nil, "foo"); err == nil {
+		t.Error("lower case value should be invalid")
+	}
+	example, err := schema.ExampleValue(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if example != "UPPER" {
+		t.Errorf("example: %v != UPPER", example)
+	}
+}
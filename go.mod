@@ -2,7 +2,7 @@ module github.com/naoyamaguchi/go-openapi
 
 require (
 	github.com/nasa9084/go-openapi v0.0.0-20191030031234-45bf58d51ed4
-	gopkg.in/yaml.v2 v2.0.0-20171116090243-287cf08546ab
+	gopkg.in/yaml.v2 v2.2.8
 )
 
 go 1.13
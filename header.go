@@ -4,21 +4,21 @@ package openapi
 
 // Header Object
 type Header struct {
-	Description     string
-	Required        bool
-	Deprecated      string
-	AllowEmptyValue bool `yaml:"allowEmptyValue"`
+	Description     string `yaml:"description,omitempty"`
+	Required        bool   `yaml:"required,omitempty"`
+	Deprecated      string `yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool   `yaml:"allowEmptyValue,omitempty"`
 
-	Style         string
-	Explode       bool
-	AllowReserved bool `yaml:"allowReserved"`
-	Schema        *Schema
-	Example       interface{}
-	Examples      map[string]*Example
+	Style         string              `yaml:"style,omitempty"`
+	Explode       *bool               `yaml:"explode,omitempty"`
+	AllowReserved bool                `yaml:"allowReserved,omitempty"`
+	Schema        *Schema             `yaml:"schema,omitempty"`
+	Example       interface{}         `yaml:"example,omitempty"`
+	Examples      map[string]*Example `yaml:"examples,omitempty"`
 
-	Content map[string]*MediaType
+	Content map[string]*MediaType `yaml:"content,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 }
 
 // Validate the values of Header object.
@@ -30,9 +30,15 @@ func (header Header) Validate() error {
 	if v, ok := header.Example.(validater); ok {
 		validaters = append(validaters, v)
 	}
+	for _, example := range header.Examples {
+		if example != nil {
+			validaters = append(validaters, example)
+		}
+	}
 
-	// example has no validation
-
+	if header.Example != nil && len(header.Examples) > 0 {
+		return ErrExampleAndExamples
+	}
 	if len(header.Content) > 1 {
 		return ErrTooManyHeaderContent
 	}
@@ -3,7 +3,7 @@ package openapi_test
 import (
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestHeader_Validate(t *testing.T) {
@@ -15,6 +15,10 @@ func TestHeader_Validate(t *testing.T) {
 				"image/png":        &openapi.MediaType{},
 			},
 		}, openapi.ErrTooManyHeaderContent},
+		{"exampleAndExamples", openapi.Header{
+			Example:  "foo",
+			Examples: map[string]*openapi.Example{"foo": {Value: "foo"}},
+		}, openapi.ErrExampleAndExamples},
 	}
 	testValidater(t, candidates)
 }
@@ -8,12 +8,12 @@ import (
 
 // Info Object
 type Info struct {
-	Title          string
-	Description    string
-	TermsOfService string `yaml:"termsOfService"`
-	Contact        *Contact
-	License        *License
-	Version        string
+	Title          string   `yaml:"title,omitempty"`
+	Description    string   `yaml:"description,omitempty"`
+	TermsOfService string   `yaml:"termsOfService,omitempty"`
+	Contact        *Contact `yaml:"contact,omitempty"`
+	License        *License `yaml:"license,omitempty"`
+	Version        string   `yaml:"version,omitempty"`
 }
 
 // Validate the values of Info object.
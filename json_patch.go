@@ -0,0 +1,237 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// PatchOperation is an operation of JSON Patch (RFC 6902).
+type PatchOperation struct {
+	Op    string      `yaml:"op"`
+	Path  string      `yaml:"path"`
+	From  string      `yaml:"from,omitempty"`
+	Value interface{} `yaml:"value,omitempty"`
+}
+
+// LoadJSONPatch loads a JSON Patch document, which is an array of
+// the operations. YAML is also accepted.
+func LoadJSONPatch(b []byte) ([]*PatchOperation, error) {
+	var ops []*PatchOperation
+	if err := yaml.Unmarshal(b, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// ApplyJSONPatch applies the JSON Patch operations to the document and
+// returns a new document. The document itself is not modified.
+// The operations are applied atomically: if any of the operations cannot
+// be applied, for example the target location does not exist or a test
+// operation fails, ErrPatchFailed is returned.
+func (doc *Document) ApplyJSONPatch(ops []*PatchOperation) (*Document, error) {
+	v, err := doc.toValue()
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		v, err = applyPatchOperation(v, op)
+		if err != nil {
+			return nil, ErrPatchFailed{Index: i, Op: op.Op, Path: op.Path, Reason: err.Error()}
+		}
+	}
+	return documentFromValue(v)
+}
+
+func applyPatchOperation(v interface{}, op *PatchOperation) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		return addJSONValue(v, op.Path, yamlToJSONValue(op.Value))
+	case "remove":
+		v, _, err := removeJSONValue(v, op.Path)
+		return v, err
+	case "replace":
+		if _, err := evaluateJSONPointer(v, op.Path); err != nil {
+			return nil, err
+		}
+		v, _, err := removeJSONValue(v, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return addJSONValue(v, op.Path, yamlToJSONValue(op.Value))
+	case "move":
+		v, removed, err := removeJSONValue(v, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return addJSONValue(v, op.Path, removed)
+	case "copy":
+		value, err := evaluateJSONPointer(v, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return addJSONValue(v, op.Path, copyJSONValue(value))
+	case "test":
+		value, err := evaluateJSONPointer(v, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(normalizeNumbers(value), normalizeNumbers(yamlToJSONValue(op.Value))) {
+			return nil, errPatchTestFailed
+		}
+		return v, nil
+	}
+	return nil, ErrMustOneOf{Object: "op", ValidValues: []string{"add", "remove", "replace", "move", "copy", "test"}}
+}
+
+const errPatchTestFailed errString = "test failed"
+
+// modifyJSONValue calls fn with the container of the location referred by
+// the pointer and the last reference token, and replaces the container
+// with the returned value. The new root value is returned.
+func modifyJSONValue(v interface{}, tokens []string, fn func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(v, tokens[0])
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		child, ok := c[tokens[0]]
+		if !ok {
+			return nil, ErrFormatInvalid{Target: "path " + tokens[0]}
+		}
+		modified, err := modifyJSONValue(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[tokens[0]] = modified
+		return c, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(c)-1)
+		if err != nil {
+			return nil, err
+		}
+		modified, err := modifyJSONValue(c[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = modified
+		return c, nil
+	}
+	return nil, ErrFormatInvalid{Target: "path " + tokens[0]}
+}
+
+// arrayIndex parses the reference token as an array index up to max.
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || max < i || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrFormatInvalid{Target: "array index " + token}
+	}
+	return i, nil
+}
+
+func addJSONValue(v interface{}, ptr string, value interface{}) (interface{}, error) {
+	tokens, err := splitJSONPointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return modifyJSONValue(v, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			if token == "-" {
+				return append(c, value), nil
+			}
+			i, err := arrayIndex(token, len(c))
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		return nil, ErrFormatInvalid{Target: "path " + token}
+	})
+}
+
+// removeJSONValue removes the value referred by the pointer, and returns
+// the new root value and the removed value.
+func removeJSONValue(v interface{}, ptr string) (interface{}, interface{}, error) {
+	tokens, err := splitJSONPointer(ptr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, v, nil
+	}
+	var removed interface{}
+	v, err = modifyJSONValue(v, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			value, ok := c[token]
+			if !ok {
+				return nil, ErrFormatInvalid{Target: "path " + token}
+			}
+			removed = value
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, ErrFormatInvalid{Target: "path " + token}
+	})
+	return v, removed, err
+}
+
+// copyJSONValue returns a deep copy of the generic value.
+func copyJSONValue(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, val := range c {
+			m[k] = copyJSONValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, val := range c {
+			s[i] = copyJSONValue(val)
+		}
+		return s
+	}
+	return v
+}
+
+// normalizeNumbers converts the numbers in the generic value into
+// float64 so that the values decoded from YAML and JSON can be compared.
+func normalizeNumbers(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, val := range c {
+			m[k] = normalizeNumbers(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, val := range c {
+			s[i] = normalizeNumbers(val)
+		}
+		return s
+	}
+	if f, ok := toFloat(v); ok {
+		return f
+	}
+	return v
+}
@@ -0,0 +1,158 @@
+package openapi_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestDocument_ApplyJSONPatch(t *testing.T) {
+	doc, err := openapi.Load([]byte(pruneSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := openapi.LoadJSONPatch([]byte(`[
+  {"op": "test", "path": "/info/title", "value": "prune"},
+  {"op": "replace", "path": "/info/title", "value": "patched"},
+  {"op": "add", "path": "/servers", "value": [{"url": "https://example.com"}]},
+  {"op": "add", "path": "/servers/0", "value": {"url": "https://first.example.com"}},
+  {"op": "remove", "path": "/components/schemas/Orphan"},
+  {"op": "move", "from": "/components/schemas/OrphanChild", "path": "/components/schemas/Child"},
+  {"op": "copy", "from": "/components/schemas/Cat", "path": "/components/schemas/Cat~1Copy"}
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := doc.ApplyJSONPatch(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patched.Info.Title != "patched" {
+		t.Errorf("%s != patched", patched.Info.Title)
+	}
+	var urls []string
+	for _, server := range patched.Servers {
+		urls = append(urls, server.URL)
+	}
+	if expected := []string{"https://first.example.com", "https://example.com"}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("%v != %v", urls, expected)
+	}
+	if got, expected := sortedKeysOf(patched.Components.Schemas), []string{"Cat", "Cat/Copy", "Child", "Dog", "Limit", "Pet", "Pets"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("%v != %v", got, expected)
+	}
+	if patched.Security[0].Names()[0] != "api_key" {
+		t.Error("security requirements should be linked")
+	}
+	if doc.Info.Title != "prune" || len(doc.Components.Schemas) != 7 {
+		t.Error("original document should not be modified")
+	}
+}
+
+func TestDocument_ApplyJSONPatch_EmptyValues(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: empty values
+  version: 1.0.0
+security:
+  - api_key: []
+paths:
+  /health:
+    get:
+      security: []
+      parameters:
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+            default: false
+        - name: q
+          in: query
+          schema:
+            type: string
+            maxLength: 0
+      responses:
+        '204':
+          description: healthy
+components:
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := openapi.LoadJSONPatch([]byte(`[
+  {"op": "test", "path": "/paths/~1health/get/security", "value": []},
+  {"op": "test", "path": "/paths/~1health/get/parameters/0/schema/default", "value": false},
+  {"op": "test", "path": "/paths/~1health/get/parameters/1/schema/maxLength", "value": 0},
+  {"op": "replace", "path": "/info/title", "value": "patched"}
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := doc.ApplyJSONPatch(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if security := patched.Paths["/health"].Get.Security; security == nil || len(*security) != 0 {
+		t.Errorf("unexpected security: %+v", security)
+	}
+	b, err := patched.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{`"security":[]`, `"default":false`, `"maxLength":0`} {
+		t.Run(strconv.Itoa(i)+"/"+want, func(t *testing.T) {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("%s is not in: %s", want, b)
+			}
+		})
+	}
+}
+
+func TestDocument_ApplyJSONPatch_Error(t *testing.T) {
+	doc, err := openapi.Load([]byte(pruneSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := []struct {
+		label string
+		op    *openapi.PatchOperation
+		err   error
+	}{
+		{"testFailed", &openapi.PatchOperation{Op: "test", Path: "/info/title", Value: "foo"}, openapi.ErrPatchFailed{Index: 1, Op: "test", Path: "/info/title", Reason: "test failed"}},
+		{"removeNotFound", &openapi.PatchOperation{Op: "remove", Path: "/components/schemas/Bird"}, openapi.ErrPatchFailed{Index: 1, Op: "remove", Path: "/components/schemas/Bird", Reason: "path Bird format is invalid"}},
+		{"replaceNotFound", &openapi.PatchOperation{Op: "replace", Path: "/info/summary", Value: "foo"}, openapi.ErrPatchFailed{Index: 1, Op: "replace", Path: "/info/summary"}},
+		{"indexOutOfRange", &openapi.PatchOperation{Op: "add", Path: "/security/2", Value: map[string]interface{}{}}, openapi.ErrPatchFailed{Index: 1, Op: "add", Path: "/security/2", Reason: "array index 2 format is invalid"}},
+		{"unknownOp", &openapi.PatchOperation{Op: "merge", Path: "/info"}, openapi.ErrPatchFailed{Index: 1, Op: "merge", Path: "/info"}},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			ops := []*openapi.PatchOperation{
+				{Op: "replace", Path: "/info/title", Value: "patched"},
+				c.op,
+			}
+			_, err := doc.ApplyJSONPatch(ops)
+			pfe, ok := err.(openapi.ErrPatchFailed)
+			if !ok {
+				t.Fatalf("%v is not ErrPatchFailed", err)
+			}
+			expected := c.err.(openapi.ErrPatchFailed)
+			if expected.Reason == "" {
+				pfe.Reason = ""
+			}
+			if !reflect.DeepEqual(pfe, expected) {
+				t.Errorf("%v != %v", pfe, expected)
+			}
+		})
+	}
+	if doc.Info.Title != "prune" {
+		t.Error("original document should not be modified")
+	}
+}
@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// splitJSONPointer splits a JSON pointer (RFC 6901) into the
+// unescaped reference tokens.
+func splitJSONPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, ErrFormatInvalid{Target: "json pointer", Format: "RFC 6901"}
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i := range tokens {
+		tokens[i] = jsonPointerUnescaper.Replace(tokens[i])
+	}
+	return tokens, nil
+}
+
+// evaluateJSONPointer returns the value in the decoded JSON value
+// referred by the JSON pointer.
+func evaluateJSONPointer(v interface{}, ptr string) (interface{}, error) {
+	tokens, err := splitJSONPointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			next, ok := t[token]
+			if !ok {
+				return nil, errors.New("not found: " + token)
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || len(t) <= i {
+				return nil, errors.New("index out of range: " + token)
+			}
+			v = t[i]
+		default:
+			return nil, errors.New("not found: " + token)
+		}
+	}
+	return v, nil
+}
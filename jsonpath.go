@@ -0,0 +1,366 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathNode is a value selected by a JSONPath with its location as
+// the JSON pointer reference tokens.
+type jsonPathNode struct {
+	value  interface{}
+	tokens []string
+}
+
+// jsonPathSegment is a segment of JSONPath like .name, [0] or [?(@.a == 'b')].
+type jsonPathSegment struct {
+	descendant bool
+	wildcard   bool
+	names      []string
+	indexes    []int
+	filter     *jsonPathFilter
+}
+
+// jsonPathFilter is a filter expression like @.a.b == 'c' or @.a.
+type jsonPathFilter struct {
+	path    []string
+	op      string
+	operand interface{}
+}
+
+// parseJSONPath parses the subset of JSONPath: the root ($), child names
+// (.name, ['name']), wildcards (.*, [*]), array indexes ([0]), unions
+// (['a','b']), descendants (..name) and filters comparing a child with
+// a literal ([?(@.name == 'value')]) or testing existence ([?(@.name)]).
+func parseJSONPath(path string) ([]*jsonPathSegment, error) {
+	invalid := ErrFormatInvalid{Target: "JSONPath " + path}
+	if !strings.HasPrefix(path, "$") {
+		return nil, invalid
+	}
+	s := path[1:]
+	var segments []*jsonPathSegment
+	for s != "" {
+		seg := &jsonPathSegment{}
+		switch {
+		case strings.HasPrefix(s, ".."):
+			seg.descendant = true
+			s = s[2:]
+			if strings.HasPrefix(s, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(s, "."):
+			s = strings.TrimPrefix(s, ".")
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			name := s[:end]
+			s = s[end:]
+			if name == "" {
+				return nil, invalid
+			}
+			if name == "*" {
+				seg.wildcard = true
+			} else {
+				seg.names = []string{name}
+			}
+			segments = append(segments, seg)
+			continue
+		}
+		if !strings.HasPrefix(s, "[") {
+			return nil, invalid
+		}
+		end := closingBracket(s)
+		if end == -1 {
+			return nil, invalid
+		}
+		if err := seg.parseBracket(strings.TrimSpace(s[1:end])); err != nil {
+			return nil, invalid
+		}
+		s = s[end+1:]
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// closingBracket returns the index of the bracket which closes the
+// first bracket, skipping quoted strings.
+func closingBracket(s string) int {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (seg *jsonPathSegment) parseBracket(s string) error {
+	if s == "*" {
+		seg.wildcard = true
+		return nil
+	}
+	if strings.HasPrefix(s, "?") {
+		expr := strings.TrimSpace(s[1:])
+		if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+			expr = expr[1 : len(expr)-1]
+		}
+		filter, err := parseJSONPathFilter(strings.TrimSpace(expr))
+		if err != nil {
+			return err
+		}
+		seg.filter = filter
+		return nil
+	}
+	for _, item := range splitOutsideQuotes(s, ',') {
+		item = strings.TrimSpace(item)
+		if v, ok := unquote(item); ok {
+			seg.names = append(seg.names, v)
+			continue
+		}
+		i, err := strconv.Atoi(item)
+		if err != nil {
+			return err
+		}
+		seg.indexes = append(seg.indexes, i)
+	}
+	return nil
+}
+
+func parseJSONPathFilter(expr string) (*jsonPathFilter, error) {
+	filter := &jsonPathFilter{}
+	lhs := expr
+	for _, op := range []string{"==", "!="} {
+		if i := indexOutsideQuotes(expr, op); i != -1 {
+			lhs = strings.TrimSpace(expr[:i])
+			operand, err := parseJSONPathLiteral(strings.TrimSpace(expr[i+len(op):]))
+			if err != nil {
+				return nil, err
+			}
+			filter.op = op
+			filter.operand = operand
+			break
+		}
+	}
+	if !strings.HasPrefix(lhs, "@") {
+		return nil, ErrFormatInvalid{Target: "filter " + expr}
+	}
+	for _, name := range strings.Split(lhs[1:], ".")[1:] {
+		if name == "" {
+			return nil, ErrFormatInvalid{Target: "filter " + expr}
+		}
+		filter.path = append(filter.path, name)
+	}
+	return filter, nil
+}
+
+func parseJSONPathLiteral(s string) (interface{}, error) {
+	if v, ok := unquote(s); ok {
+		return v, nil
+	}
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+// splitOutsideQuotes splits s by sep which is not in quoted strings.
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// indexOutsideQuotes returns the index of the first sub which is not in
+// quoted strings, or -1.
+func indexOutsideQuotes(s, sub string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(s[i:], sub):
+			return i
+		}
+	}
+	return -1
+}
+
+// evaluateJSONPath returns the nodes selected by the JSONPath in the
+// generic value, in document order.
+func evaluateJSONPath(v interface{}, path string) ([]jsonPathNode, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []jsonPathNode{{value: v}}
+	for _, seg := range segments {
+		var next []jsonPathNode
+		for _, node := range nodes {
+			if seg.descendant {
+				for _, d := range descendants(node) {
+					next = append(next, seg.selectChildren(d)...)
+				}
+				continue
+			}
+			next = append(next, seg.selectChildren(node)...)
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// descendants returns the node itself and all the descendant nodes.
+func descendants(node jsonPathNode) []jsonPathNode {
+	nodes := []jsonPathNode{node}
+	for _, child := range children(node) {
+		nodes = append(nodes, descendants(child)...)
+	}
+	return nodes
+}
+
+func children(node jsonPathNode) []jsonPathNode {
+	var nodes []jsonPathNode
+	switch c := node.value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(c) {
+			nodes = append(nodes, jsonPathNode{value: c[k], tokens: childTokens(node.tokens, k)})
+		}
+	case []interface{}:
+		for i, v := range c {
+			nodes = append(nodes, jsonPathNode{value: v, tokens: childTokens(node.tokens, strconv.Itoa(i))})
+		}
+	}
+	return nodes
+}
+
+func childTokens(tokens []string, token string) []string {
+	return append(append(make([]string, 0, len(tokens)+1), tokens...), token)
+}
+
+func (seg *jsonPathSegment) selectChildren(node jsonPathNode) []jsonPathNode {
+	if seg.wildcard {
+		return children(node)
+	}
+	if seg.filter != nil {
+		var nodes []jsonPathNode
+		for _, child := range children(node) {
+			if seg.filter.match(child.value) {
+				nodes = append(nodes, child)
+			}
+		}
+		return nodes
+	}
+	var nodes []jsonPathNode
+	switch c := node.value.(type) {
+	case map[string]interface{}:
+		for _, name := range seg.names {
+			if v, ok := c[name]; ok {
+				nodes = append(nodes, jsonPathNode{value: v, tokens: childTokens(node.tokens, name)})
+			}
+		}
+	case []interface{}:
+		for _, i := range seg.indexes {
+			if i < 0 {
+				i += len(c)
+			}
+			if 0 <= i && i < len(c) {
+				nodes = append(nodes, jsonPathNode{value: c[i], tokens: childTokens(node.tokens, strconv.Itoa(i))})
+			}
+		}
+	}
+	return nodes
+}
+
+func (filter *jsonPathFilter) match(v interface{}) bool {
+	for _, name := range filter.path {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if v, ok = obj[name]; !ok {
+			return false
+		}
+	}
+	switch filter.op {
+	case "==":
+		return reflect.DeepEqual(normalizeNumbers(v), filter.operand)
+	case "!=":
+		return !reflect.DeepEqual(normalizeNumbers(v), filter.operand)
+	}
+	return true
+}
+
+// sortNodesForRemoval sorts the nodes so that removing them in order does
+// not change the locations of the remaining nodes: descendants come
+// before their ancestors, and latter array elements come first.
+func sortNodesForRemoval(nodes []jsonPathNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].tokens, nodes[j].tokens
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] == b[k] {
+				continue
+			}
+			ai, aerr := strconv.Atoi(a[k])
+			bi, berr := strconv.Atoi(b[k])
+			if aerr == nil && berr == nil {
+				return ai > bi
+			}
+			return a[k] > b[k]
+		}
+		return len(a) > len(b)
+	})
+}
@@ -8,8 +8,8 @@ import (
 
 // License Object
 type License struct {
-	Name string
-	URL  string
+	Name string `yaml:"name,omitempty"`
+	URL  string `yaml:"url,omitempty"`
 }
 
 // Validate the values of License object.
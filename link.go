@@ -1,19 +1,23 @@
 package openapi
 
-import "errors"
+import (
+	"errors"
+	"sort"
+	"strings"
+)
 
 // codebeat:disable[TOO_MANY_IVARS]
 
 // Link Object
 type Link struct {
-	OperationRef string `yaml:"operationRef"`
-	OperationID  string `yaml:"operationId"`
-	Parameters   map[string]interface{}
-	RequestBody  interface{} `yaml:"requestBody"`
-	Description  string
-	Server       *Server
-
-	Ref string `yaml:"$ref"`
+	OperationRef string                 `yaml:"operationRef,omitempty"`
+	OperationID  string                 `yaml:"operationId,omitempty"`
+	Parameters   map[string]interface{} `yaml:"parameters,omitempty"`
+	RequestBody  interface{}            `yaml:"requestBody,omitempty"`
+	Description  string                 `yaml:"description,omitempty"`
+	Server       *Server                `yaml:"server,omitempty"`
+
+	Ref string `yaml:"$ref,omitempty"`
 }
 
 // Validate the values of Link object.
@@ -21,6 +25,9 @@ func (link Link) Validate() error {
 	if link.OperationRef != "" && link.OperationID != "" {
 		return errors.New("operationRef and operationId are mutually exclusive")
 	}
+	if err := link.validateExpressions(); err != nil {
+		return err
+	}
 	validaters := []validater{}
 	for _, i := range link.Parameters {
 		if v, ok := i.(validater); ok {
@@ -35,3 +42,93 @@ func (link Link) Validate() error {
 	}
 	return validateAll(validaters)
 }
+
+// validateExpressions validates the runtime expressions in the
+// parameters and the request body are well-formed. The strings in objects
+// and arrays are validated recursively.
+func (link Link) validateExpressions() error {
+	values := []interface{}{link.RequestBody}
+	for _, v := range link.Parameters {
+		values = append(values, v)
+	}
+	for _, v := range values {
+		if err := validateLinkValue(yamlToJSONValue(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateLinkValue(v interface{}) error {
+	switch t := v.(type) {
+	case string:
+		if isRuntimeExpression(t) {
+			_, err := ParseRuntimeExpression(t)
+			return err
+		}
+		if strings.Contains(t, "{$") && !matchRuntimeExpression(t) {
+			return ErrRuntimeExprFormat
+		}
+	case map[string]interface{}:
+		for _, val := range t {
+			if err := validateLinkValue(val); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, val := range t {
+			if err := validateLinkValue(val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateTarget validates the operation which the link targets exists
+// in the document, and each key in link.parameters names a parameter of
+// the operation. The key can be qualified with the parameter location
+// like path.id. The operationRef to another document is not validated.
+func (link Link) validateTarget(doc *Document) error {
+	if link.Ref != "" {
+		return nil // validated in doc.Components
+	}
+	if link.OperationRef == "" && link.OperationID == "" || link.isExternal() {
+		return nil
+	}
+	_, _, pathItem, op, err := doc.resolveLinkTarget(&link)
+	if err != nil {
+		return err
+	}
+	params, err := operationParameters(doc, pathItem, op)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for name := range link.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !hasLinkParameter(params, name) {
+			return ErrParameterNotDeclared{Name: name}
+		}
+	}
+	return nil
+}
+
+// isExternal reports whether the operationRef of the link refers an
+// operation in another document, like
+// https://example.com/openapi.yaml#/paths/~1users/get.
+func (link Link) isExternal() bool {
+	return link.OperationRef != "" && !strings.HasPrefix(link.OperationRef, "#")
+}
+
+func hasLinkParameter(params []*Parameter, name string) bool {
+	for _, p := range params {
+		if p.Name == name || string(p.In)+"."+p.Name == name {
+			return true
+		}
+	}
+	return false
+}
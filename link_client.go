@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// LinkClient sends requests to the operations in the document and
+// follows the links in the responses.
+// It is intended for scripted API walkthroughs in tests.
+type LinkClient struct {
+	Document *Document
+	// Client is used to send the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// BaseURL overrides the server URL of the operations.
+	// If empty, the server of the link or the first server of the
+	// operation is used, expanded with the default values.
+	BaseURL string
+}
+
+// Do sends a request to the operation which has given operationId.
+// The params are keyed by parameter name, or location and name like
+// "path.id" if the name is ambiguous.
+// The returned context contains the request and the response with its body,
+// and can be passed to Follow.
+func (c *LinkClient) Do(ctx context.Context, operationID string, params map[string]interface{}, body interface{}) (*RuntimeContext, error) {
+	path, method, pathItem, op, ok := c.Document.Paths.findOperationByID(operationID)
+	if !ok {
+		return nil, ErrOperationNotFound
+	}
+	return c.do(ctx, nil, path, method, pathItem, op, params, body)
+}
+
+// Follow follows the link named linkName in the response object, which
+// describes the response captured in rc. The parameters and the request
+// body of the link are evaluated against rc, and a request to the target
+// operation is sent.
+func (c *LinkClient) Follow(ctx context.Context, rc *RuntimeContext, response *Response, linkName string) (*RuntimeContext, error) {
+	link, ok := response.Links[linkName]
+	if !ok || link == nil {
+		return nil, ErrLinkNotFound
+	}
+	if link.Ref != "" {
+		resolved, err := ResolveLink(c.Document, link.Ref)
+		if err != nil {
+			return nil, err
+		}
+		link = resolved
+	}
+	if link.isExternal() {
+		return nil, ErrExternalOperationRef
+	}
+	path, method, pathItem, op, err := c.Document.resolveLinkTarget(link)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{}
+	for name, v := range link.Parameters {
+		evaluated, err := evaluateLinkValue(v, rc)
+		if err != nil {
+			return nil, err
+		}
+		params[name] = evaluated
+	}
+	body, err := evaluateLinkValue(link.RequestBody, rc)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, link.Server, path, method, pathItem, op, params, body)
+}
+
+// resolveLinkTarget returns the operation which the link targets.
+func (doc *Document) resolveLinkTarget(link *Link) (string, string, *PathItem, *Operation, error) {
+	if link.OperationRef != "" {
+		return resolveOperationRef(doc, link.OperationRef)
+	}
+	path, method, pathItem, op, ok := doc.Paths.findOperationByID(link.OperationID)
+	if !ok {
+		return "", "", nil, nil, ErrOperationNotFound
+	}
+	return path, method, pathItem, op, nil
+}
+
+// evaluateLinkValue evaluates a link parameter or request body value.
+// A string value is treated as a runtime expression if it starts with
+// an expression type like "$request.", or as a template if it contains
+// embedded expressions. The strings in objects and arrays are evaluated
+// recursively.
+func evaluateLinkValue(v interface{}, rc *RuntimeContext) (interface{}, error) {
+	switch t := yamlToJSONValue(v).(type) {
+	case string:
+		if isRuntimeExpression(t) {
+			return EvaluateRuntimeExpression(t, rc)
+		}
+		if strings.Contains(t, "{$") {
+			return ExpandRuntimeExpression(t, rc)
+		}
+		return t, nil
+	case map[string]interface{}:
+		for k, val := range t {
+			evaluated, err := evaluateLinkValue(val, rc)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = evaluated
+		}
+		return t, nil
+	case []interface{}:
+		for i, val := range t {
+			evaluated, err := evaluateLinkValue(val, rc)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = evaluated
+		}
+		return t, nil
+	}
+	return v, nil
+}
+
+func (c *LinkClient) do(ctx context.Context, server *Server, path, method string, pathItem *PathItem, op *Operation, params map[string]interface{}, body interface{}) (*RuntimeContext, error) {
+	parameters, err := operationParameters(c.Document, pathItem, op)
+	if err != nil {
+		return nil, err
+	}
+	base, err := c.baseURL(server, pathItem, op)
+	if err != nil {
+		return nil, err
+	}
+
+	pathParams := map[string]string{}
+	var query []string
+	header := http.Header{}
+	for _, p := range parameters {
+		v, ok := params[string(p.In)+"."+p.Name]
+		if !ok {
+			v, ok = params[p.Name]
+		}
+		if !ok {
+			if p.Required {
+				return nil, ErrRequired{Target: "parameter " + p.Name}
+			}
+			continue
+		}
+		s, err := p.Serialize(v)
+		if err != nil {
+			return nil, err
+		}
+		switch p.In {
+		case InPath:
+			path = strings.Replace(path, "{"+p.Name+"}", s, -1)
+			pathParams[p.Name] = stringifyValue(v)
+		case InQuery:
+			query = append(query, s)
+		case InHeader:
+			header.Set(p.Name, s)
+		case InCookie:
+			header.Add("Cookie", s)
+		}
+	}
+	u := strings.TrimSuffix(base, "/") + path
+	if len(query) > 0 {
+		u += "?" + strings.Join(query, "&")
+	}
+
+	contentType, payload, err := encodeRequestBody(c.Document, op.RequestBody, body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header = header
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &RuntimeContext{
+		Request:      req,
+		RequestBody:  payload,
+		PathParams:   pathParams,
+		Response:     resp,
+		ResponseBody: respBody,
+	}, nil
+}
+
+func (c *LinkClient) baseURL(server *Server, pathItem *PathItem, op *Operation) (string, error) {
+	if c.BaseURL != "" {
+		return c.BaseURL, nil
+	}
+	if server == nil {
+		servers := effectiveServers(c.Document, pathItem, op)
+		if len(servers) == 0 {
+			return "", ErrRequired{Target: "server"}
+		}
+		server = servers[0]
+	}
+	base, err := server.Expand(nil)
+	if err != nil {
+		return "", err
+	}
+	if u, err := url.Parse(base); err != nil || !u.IsAbs() {
+		return "", ErrFormatInvalid{Target: "server.url", Format: "absolute URL"}
+	}
+	return base, nil
+}
@@ -0,0 +1,174 @@
+package openapi_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestLinkClient(t *testing.T) {
+	doc, err := openapi.LoadFile("test/link-example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2.0/users/alice":
+			fmt.Fprint(w, `{"username": "alice", "uuid": "u-1"}`)
+		case "/2.0/repositories/alice":
+			fmt.Fprint(w, `[{"slug": "repo", "owner": {"username": "alice"}}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := &openapi.LinkClient{Document: doc, Client: srv.Client(), BaseURL: srv.URL}
+	ctx := context.Background()
+	rc, err := client.Do(ctx, "getUserByName", map[string]interface{}{"username": "alice"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Response.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: %d", rc.Response.StatusCode)
+	}
+	if rc.PathParams["username"] != "alice" {
+		t.Errorf("unexpected path params: %+v", rc.PathParams)
+	}
+
+	response := doc.Paths["/2.0/users/{username}"].Get.Responses["200"]
+	next, err := client.Follow(ctx, rc, response, "userRepositories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Request.URL.Path != "/2.0/repositories/alice" {
+		t.Errorf("unexpected request path: %s", next.Request.URL.Path)
+	}
+	if string(next.ResponseBody) != `[{"slug": "repo", "owner": {"username": "alice"}}]` {
+		t.Errorf("unexpected response body: %s", next.ResponseBody)
+	}
+
+	if _, err := client.Follow(ctx, rc, response, "unknown"); err != openapi.ErrLinkNotFound {
+		t.Errorf("error should be %v, but %v", openapi.ErrLinkNotFound, err)
+	}
+	external := &openapi.Response{Links: map[string]*openapi.Link{
+		"external": &openapi.Link{OperationRef: "https://na2.gigantic-server.com/#/paths/~12.0~1repositories~1{username}/get"},
+	}}
+	if _, err := client.Follow(ctx, rc, external, "external"); err != openapi.ErrExternalOperationRef {
+		t.Errorf("error should be %v, but %v", openapi.ErrExternalOperationRef, err)
+	}
+	if _, err := client.Do(ctx, "unknown", nil, nil); err != openapi.ErrOperationNotFound {
+		t.Errorf("error should be %v, but %v", openapi.ErrOperationNotFound, err)
+	}
+	if _, err := client.Do(ctx, "getUserByName", nil, nil); err != (openapi.ErrRequired{Target: "parameter username"}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLinkClient_RequestBody(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: users
+  version: 1.0.0
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getUser
+      responses:
+        '200':
+          description: user
+          links:
+            rename:
+              operationRef: '#/paths/~1users~1%7Bid%7D/put'
+              parameters:
+                id: $response.body#/id
+              requestBody:
+                user:
+                  name: $response.body#/name
+                tags: [$method, $5]
+    put:
+      operationId: updateUser
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        '204':
+          description: updated
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			b, _ := ioutil.ReadAll(r.Body)
+			received = string(b)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "u1", "name": "alice"}`)
+	}))
+	defer srv.Close()
+
+	client := &openapi.LinkClient{Document: doc, Client: srv.Client(), BaseURL: srv.URL}
+	ctx := context.Background()
+	rc, err := client.Do(ctx, "getUser", map[string]interface{}{"id": "u1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := client.Follow(ctx, rc, doc.Paths["/users/{id}"].Get.Responses["200"], "rename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Request.URL.Path != "/users/u1" {
+		t.Errorf("unexpected request path: %s", next.Request.URL.Path)
+	}
+	if expected := `{"tags":["GET","$5"],"user":{"name":"alice"}}`; received != expected {
+		t.Errorf("%s != %s", received, expected)
+	}
+}
+
+func TestResolveOperation(t *testing.T) {
+	doc, err := openapi.LoadFile("test/link-example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op, err := openapi.ResolveOperation(doc, "#/paths/~12.0~1users~1{username}/get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.OperationID != "getUserByName" {
+		t.Errorf("unexpected operation: %s", op.OperationID)
+	}
+	op, err = openapi.ResolveOperation(doc, "#/paths/~12.0~1users~1%7Busername%7D/get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.OperationID != "getUserByName" {
+		t.Errorf("unexpected operation: %s", op.OperationID)
+	}
+	for _, ref := range []string{"#/paths/~12.0~1users/get", "#/paths/~12.0~1users~1{username}/post", "#/components/links/UserRepository"} {
+		if _, err := openapi.ResolveOperation(doc, ref); err == nil {
+			t.Errorf("%s should not be resolved", ref)
+		}
+	}
+}
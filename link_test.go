@@ -0,0 +1,92 @@
+package openapi_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestLink_Validate(t *testing.T) {
+	candidates := []candidate{
+		{"empty", openapi.Link{}, nil},
+		{"both operationRef and operationId", openapi.Link{OperationRef: "#/paths/~1/get", OperationID: "foo"}, errors.New("operationRef and operationId are mutually exclusive")},
+		{"valid expression", openapi.Link{OperationID: "foo", Parameters: map[string]interface{}{"id": "$response.body#/id"}}, nil},
+		{"constant", openapi.Link{OperationID: "foo", Parameters: map[string]interface{}{"id": "foo", "n": 1}}, nil},
+		{"invalid expression", openapi.Link{OperationID: "foo", Parameters: map[string]interface{}{"id": "$response.foo"}}, openapi.ErrRuntimeExprFormat},
+		{"constant with dollar", openapi.Link{OperationID: "foo", Parameters: map[string]interface{}{"price": "$5"}}, nil},
+		{"invalid embedded expression", openapi.Link{OperationID: "foo", RequestBody: "id={$foo}"}, openapi.ErrRuntimeExprFormat},
+	}
+	testValidater(t, candidates)
+}
+
+func TestDocument_ValidateLinks(t *testing.T) {
+	newDoc := func(link *openapi.Link) openapi.Document {
+		return openapi.Document{
+			Version: "3.0.0",
+			Info:    &openapi.Info{Title: "foo", Version: "1.0"},
+			Paths: openapi.Paths{
+				"/users/{id}": &openapi.PathItem{
+					Parameters: []*openapi.Parameter{
+						&openapi.Parameter{Name: "id", In: "path", Required: true},
+					},
+					Get: &openapi.Operation{
+						OperationID: "getUser",
+						Parameters: []*openapi.Parameter{
+							&openapi.Parameter{Name: "fields", In: "query"},
+						},
+						Responses: openapi.Responses{
+							"200": &openapi.Response{
+								Description: "user",
+								Links:       map[string]*openapi.Link{"self": link},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	candidates := []struct {
+		label string
+		link  *openapi.Link
+		err   error
+	}{
+		{"operationId", &openapi.Link{OperationID: "getUser", Parameters: map[string]interface{}{"id": "$response.body#/id", "query.fields": "name"}}, nil},
+		{"operationRef", &openapi.Link{OperationRef: "#/paths/~1users~1{id}/get", Parameters: map[string]interface{}{"path.id": "$response.body#/id"}}, nil},
+		{"unknown operationId", &openapi.Link{OperationID: "getUsers"}, openapi.ErrOperationNotFound},
+		{"unknown operationRef", &openapi.Link{OperationRef: "#/paths/~1users~1{id}/post"}, openapi.ErrOperationNotFound},
+		{"invalid operationRef", &openapi.Link{OperationRef: "#/paths/~1users~1{id}"}, errors.New("cannot resolve operation reference: #/paths/~1users~1{id}")},
+		{"external operationRef", &openapi.Link{OperationRef: "https://na2.gigantic-server.com/#/paths/~12.0~1repositories~1{username}/get", Parameters: map[string]interface{}{"username": "$response.body#/username"}}, nil},
+		{"unknown parameter", &openapi.Link{OperationID: "getUser", Parameters: map[string]interface{}{"name": "foo"}}, openapi.ErrParameterNotDeclared{Name: "name"}},
+		{"wrong location", &openapi.Link{OperationID: "getUser", Parameters: map[string]interface{}{"query.id": "foo"}}, openapi.ErrParameterNotDeclared{Name: "query.id"}},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			if err := newDoc(c.link).Validate(); !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %v, but %v", c.err, err)
+			}
+		})
+	}
+
+	// the links in the callbacks are validated too
+	doc := newDoc(&openapi.Link{OperationID: "getUser"})
+	doc.Paths["/users/{id}"].Get.Callbacks = map[string]*openapi.Callback{
+		"onChange": &openapi.Callback{
+			"{$request.query.callbackUrl}": &openapi.PathItem{
+				Post: &openapi.Operation{
+					Responses: openapi.Responses{
+						"200": &openapi.Response{
+							Description: "ok",
+							Links:       map[string]*openapi.Link{"user": &openapi.Link{OperationID: "getUsers"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := doc.Validate(); err != openapi.ErrOperationNotFound {
+		t.Errorf("error should be %v, but %v", openapi.ErrOperationNotFound, err)
+	}
+}
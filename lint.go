@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"strings"
+)
+
+// LintIssue is a problem found by Lint. The issues are not violations of
+// the specification, but practices which make the document hard to use.
+type LintIssue struct {
+	// Rule is the name of the rule which found the issue.
+	Rule string
+	// Pointer is the JSON pointer to the object which has the issue.
+	Pointer string
+	Message string
+}
+
+// LintRule checks the document and returns the issues found.
+type LintRule func(doc *Document) ([]*LintIssue, error)
+
+// DefaultLintRules are the rules used by Lint when no rule is given.
+var DefaultLintRules = []LintRule{
+	LintOperationID,
+	LintOperationDescription,
+	LintOperationTags,
+	LintSuccessResponse,
+	LintPathTrailingSlash,
+	LintUnusedComponents,
+}
+
+// Lint checks the document with the rules, or DefaultLintRules if no rule
+// is given, and returns the issues found in order of the rules.
+func (doc *Document) Lint(rules ...LintRule) ([]*LintIssue, error) {
+	if len(rules) == 0 {
+		rules = DefaultLintRules
+	}
+	var issues []*LintIssue
+	for _, rule := range rules {
+		found, err := rule(doc)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+// lintOperations returns the issues found by check for each operation.
+func lintOperations(doc *Document, rule string, check func(op *Operation) string) ([]*LintIssue, error) {
+	var issues []*LintIssue
+	err := doc.Walk(func(doc *Document, method, path string, pathItem *PathItem, op *Operation) error {
+		if msg := check(op); msg != "" {
+			issues = append(issues, &LintIssue{
+				Rule:    rule,
+				Pointer: childPointer(childPointer("/paths", path), strings.ToLower(method)),
+				Message: msg,
+			})
+		}
+		return nil
+	})
+	return issues, err
+}
+
+// LintOperationID reports the operations which have no operationId.
+func LintOperationID(doc *Document) ([]*LintIssue, error) {
+	return lintOperations(doc, "operation-operationId", func(op *Operation) string {
+		if op.OperationID == "" {
+			return "operation should have operationId"
+		}
+		return ""
+	})
+}
+
+// LintOperationDescription reports the operations which have neither
+// summary nor description.
+func LintOperationDescription(doc *Document) ([]*LintIssue, error) {
+	return lintOperations(doc, "operation-description", func(op *Operation) string {
+		if op.Summary == "" && op.Description == "" {
+			return "operation should have summary or description"
+		}
+		return ""
+	})
+}
+
+// LintOperationTags reports the operations which have no tags, or have
+// tags not declared in the tags of the document.
+func LintOperationTags(doc *Document) ([]*LintIssue, error) {
+	declared := map[string]bool{}
+	for _, tag := range doc.Tags {
+		declared[tag.Name] = true
+	}
+	return lintOperations(doc, "operation-tags", func(op *Operation) string {
+		if len(op.Tags) == 0 {
+			return "operation should have tags"
+		}
+		for _, tag := range op.Tags {
+			if !declared[tag] {
+				return "tag " + tag + " is not declared in document tags"
+			}
+		}
+		return ""
+	})
+}
+
+// LintSuccessResponse reports the operations which have no success
+// response, which is 2XX or default.
+func LintSuccessResponse(doc *Document) ([]*LintIssue, error) {
+	return lintOperations(doc, "operation-success-response", func(op *Operation) string {
+		if _, _, ok := op.SuccessResponse(); !ok {
+			return "operation should have a success response"
+		}
+		return ""
+	})
+}
+
+// LintPathTrailingSlash reports the paths which end with a slash.
+func LintPathTrailingSlash(doc *Document) ([]*LintIssue, error) {
+	var issues []*LintIssue
+	for _, path := range sortedMapKeys(doc.Paths) {
+		if path != "/" && strings.HasSuffix(path, "/") {
+			issues = append(issues, &LintIssue{
+				Rule:    "path-trailing-slash",
+				Pointer: childPointer("/paths", path),
+				Message: "path should not end with a slash",
+			})
+		}
+	}
+	return issues, nil
+}
+
+// LintUnusedComponents reports the components which are not reachable
+// from the paths and the security requirements.
+func LintUnusedComponents(doc *Document) ([]*LintIssue, error) {
+	unused, err := doc.UnusedComponents()
+	if err != nil {
+		return nil, err
+	}
+	var issues []*LintIssue
+	for _, kind := range componentKinds {
+		for _, name := range unused[kind] {
+			issues = append(issues, &LintIssue{
+				Rule:    "unused-components",
+				Pointer: childPointer(childPointer("/components", kind), name),
+				Message: "component is not used",
+			})
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,91 @@
+package openapi_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const lintSpec = `
+openapi: 3.0.2
+info:
+  title: lint
+  version: 1.0.0
+tags:
+  - name: pets
+paths:
+  /pets/:
+    get:
+      operationId: listPets
+      summary: list pets
+      tags: [pets]
+      responses:
+        '200':
+          description: pets
+    post:
+      tags: [animals]
+      responses:
+        '400':
+          description: bad request
+components:
+  schemas:
+    Unused:
+      type: object
+`
+
+func TestDocument_Lint(t *testing.T) {
+	doc, err := openapi.Load([]byte(lintSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := doc.Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*openapi.LintIssue{
+		{Rule: "operation-operationId", Pointer: "/paths/~1pets~1/post", Message: "operation should have operationId"},
+		{Rule: "operation-description", Pointer: "/paths/~1pets~1/post", Message: "operation should have summary or description"},
+		{Rule: "operation-tags", Pointer: "/paths/~1pets~1/post", Message: "tag animals is not declared in document tags"},
+		{Rule: "operation-success-response", Pointer: "/paths/~1pets~1/post", Message: "operation should have a success response"},
+		{Rule: "path-trailing-slash", Pointer: "/paths/~1pets~1", Message: "path should not end with a slash"},
+		{Rule: "unused-components", Pointer: "/components/schemas/Unused", Message: "component is not used"},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("%d issues are found, expected %d", len(issues), len(expected))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(issues[i], expected[i]) {
+			t.Errorf("%+v != %+v", issues[i], expected[i])
+		}
+	}
+}
+
+func TestDocument_Lint_Rules(t *testing.T) {
+	doc, err := openapi.Load([]byte(lintSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := []struct {
+		label string
+		rules []openapi.LintRule
+		count int
+	}{
+		{"operationID", []openapi.LintRule{openapi.LintOperationID}, 1},
+		{"tags", []openapi.LintRule{openapi.LintOperationTags}, 1},
+		{"trailingSlash", []openapi.LintRule{openapi.LintPathTrailingSlash, openapi.LintUnusedComponents}, 2},
+		{"custom", []openapi.LintRule{func(doc *openapi.Document) ([]*openapi.LintIssue, error) { return nil, nil }}, 0},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			issues, err := doc.Lint(c.rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != c.count {
+				t.Errorf("%d issues are found, expected %d", len(issues), c.count)
+			}
+		})
+	}
+}
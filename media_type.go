@@ -4,15 +4,18 @@ package openapi
 
 // MediaType Object
 type MediaType struct {
-	Schema   *Schema
-	Example  interface{}
-	Examples map[string]*Example
-	Encoding map[string]*Encoding
+	Schema   *Schema              `yaml:"schema,omitempty"`
+	Example  interface{}          `yaml:"example,omitempty"`
+	Examples map[string]*Example  `yaml:"examples,omitempty"`
+	Encoding map[string]*Encoding `yaml:"encoding,omitempty"`
 }
 
 // Validate the values of MediaType object.
 // This function DOES NOT check whether the encoding object is in schema or not.
 func (mediaType MediaType) Validate() error {
+	if mediaType.Example != nil && len(mediaType.Examples) > 0 {
+		return ErrExampleAndExamples
+	}
 	validaters := []validater{}
 	if mediaType.Schema != nil {
 		validaters = append(validaters, mediaType.Schema)
@@ -20,8 +23,11 @@ func (mediaType MediaType) Validate() error {
 	if v, ok := mediaType.Example.(validater); ok {
 		validaters = append(validaters, v)
 	}
-
-	// example has no validation
+	for _, example := range mediaType.Examples {
+		if example != nil {
+			validaters = append(validaters, example)
+		}
+	}
 
 	for _, e := range mediaType.Encoding {
 		validaters = append(validaters, e)
@@ -0,0 +1,22 @@
+package openapi_test
+
+import (
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestMediaType_Validate(t *testing.T) {
+	candidates := []candidate{
+		{"empty", openapi.MediaType{}, nil},
+		{"example", openapi.MediaType{Example: "foo"}, nil},
+		{"exampleAndExamples", openapi.MediaType{
+			Example:  "foo",
+			Examples: map[string]*openapi.Example{"foo": {Value: "foo"}},
+		}, openapi.ErrExampleAndExamples},
+		{"invalidExamples", openapi.MediaType{
+			Examples: map[string]*openapi.Example{"foo": {Value: "foo", ExternalValue: exampleCom}},
+		}, openapi.ErrValueAndExternalValue},
+	}
+	testValidater(t, candidates)
+}
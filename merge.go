@@ -0,0 +1,353 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ConflictStrategy specifies how Merge handles the components which have
+// the same name in the documents.
+type ConflictStrategy int
+
+const (
+	// ConflictError reports all the components with the same name as conflicts.
+	ConflictError ConflictStrategy = iota
+	// ConflictDedupe merges the components with the same name if they are
+	// identical, and reports them as conflicts otherwise.
+	ConflictDedupe
+	// ConflictRename merges the components with the same name if they are
+	// identical, and otherwise renames the latter with the prefix of the
+	// document, rewriting the references to it.
+	ConflictRename
+)
+
+// MergeOptions is the options of merging documents.
+type MergeOptions struct {
+	Strategy ConflictStrategy
+	// Prefixes are the prefixes of the documents used to rename the
+	// components with ConflictRename. If the prefix of a document is not
+	// given, the title of the document followed by "_" is used.
+	Prefixes []string
+}
+
+// Merge merges the documents with ConflictError strategy.
+// See MergeOptions.Merge for details.
+func Merge(docs ...*Document) (*Document, error) {
+	return MergeOptions{}.Merge(docs...)
+}
+
+// Merge merges the documents into a new document. The given documents
+// are not modified.
+//
+// The openapi version and the info are taken from the first document.
+// The paths and the components are combined, and the tags are combined by
+// their names. If all the documents have the same servers or security
+// requirements, they are kept in the merged document; otherwise they are
+// copied into the path items or the operations which do not override them.
+//
+// The components with the same name are handled with the strategy.
+// The operations declared for the same method and path, and the duplicated
+// operationIds are reported as conflicts. All the conflicts are returned
+// as ErrMergeConflict.
+func (opts MergeOptions) Merge(docs ...*Document) (*Document, error) {
+	if len(docs) == 0 {
+		return nil, ErrRequired{Target: "documents"}
+	}
+	m := &merger{
+		opts: opts,
+		merged: &Document{
+			Version: docs[0].Version,
+			Paths:   Paths{},
+		},
+	}
+	if docs[0].Info != nil {
+		info := *docs[0].Info
+		m.merged.Info = &info
+	}
+	cloned := make([]*Document, len(docs))
+	for i, doc := range docs {
+		doc := doc.clone()
+		if err := m.mergeComponents(i, doc); err != nil {
+			return nil, err
+		}
+		cloned[i] = doc
+	}
+	// compare after the security schemes are renamed
+	commonServers, commonSecurity := true, true
+	for _, doc := range cloned[1:] {
+		commonServers = commonServers && reflect.DeepEqual(doc.Servers, cloned[0].Servers)
+		commonSecurity = commonSecurity && equalSecurityRequirements(doc.Security, cloned[0].Security)
+	}
+	for _, doc := range cloned {
+		if commonServers {
+			m.merged.Servers = doc.Servers
+		} else {
+			pushDownServers(doc)
+		}
+		if commonSecurity {
+			m.merged.Security = doc.Security
+		} else {
+			pushDownSecurity(doc)
+		}
+		m.mergePaths(doc)
+		m.mergeTags(doc)
+		if m.merged.ExternalDocs == nil {
+			m.merged.ExternalDocs = doc.ExternalDocs
+		}
+	}
+	m.checkOperationIDs()
+	if len(m.conflicts) > 0 {
+		return nil, ErrMergeConflict{Conflicts: m.conflicts}
+	}
+	m.merged.linkSecurityRequirements()
+	return m.merged, nil
+}
+
+type merger struct {
+	opts      MergeOptions
+	merged    *Document
+	conflicts []string
+}
+
+func (m *merger) conflict(target string) {
+	m.conflicts = append(m.conflicts, target)
+}
+
+func (m *merger) prefix(i int, doc *Document) string {
+	if i < len(m.opts.Prefixes) && m.opts.Prefixes[i] != "" {
+		return m.opts.Prefixes[i]
+	}
+	if doc.Info != nil && doc.Info.Title != "" {
+		return strings.Map(func(r rune) rune {
+			if mapKeyRegexp.MatchString(string(r)) {
+				return r
+			}
+			return '_'
+		}, doc.Info.Title) + "_"
+	}
+	return "doc" + strconv.Itoa(i) + "_"
+}
+
+// mergeComponents merges the components of the document. If some
+// components are renamed, the references in the document are rewritten.
+func (m *merger) mergeComponents(i int, doc *Document) error {
+	if doc.Components == nil {
+		return nil
+	}
+	if m.merged.Components == nil {
+		m.merged.Components = &Components{}
+	}
+	src := reflect.ValueOf(doc.Components).Elem()
+	dst := reflect.ValueOf(m.merged.Components).Elem()
+	refRenames := map[string]string{}
+	if m.opts.Strategy == ConflictRename {
+		var err error
+		if refRenames, err = m.renameComponentRefs(i, doc, src, dst); err != nil {
+			return err
+		}
+	}
+	schemeRenames := map[string]string{}
+	for f := 0; f < src.NumField(); f++ {
+		if src.Type().Field(f).PkgPath != "" {
+			continue
+		}
+		kind := yamlFieldName(src.Type().Field(f))
+		srcMap, dstMap := src.Field(f), dst.Field(f)
+		if srcMap.Len() == 0 {
+			continue
+		}
+		if dstMap.IsNil() {
+			dstMap.Set(reflect.MakeMap(srcMap.Type()))
+		}
+		for _, name := range sortedMapKeys(srcMap.Interface()) {
+			key := reflect.ValueOf(name)
+			value := srcMap.MapIndex(key)
+			existing := dstMap.MapIndex(key)
+			if !existing.IsValid() {
+				dstMap.SetMapIndex(key, value)
+				continue
+			}
+			if _, ok := refRenames[componentRef(kind, name)]; !ok {
+				if m.opts.Strategy != ConflictError && reflect.DeepEqual(existing.Interface(), value.Interface()) {
+					continue
+				}
+				m.conflict("components." + kind + "." + name)
+				continue
+			}
+			renamed := m.prefix(i, doc) + name
+			if dstMap.MapIndex(reflect.ValueOf(renamed)).IsValid() {
+				m.conflict("components." + kind + "." + renamed)
+				continue
+			}
+			dstMap.SetMapIndex(reflect.ValueOf(renamed), value)
+			if kind == "securitySchemes" {
+				schemeRenames[name] = renamed
+			}
+		}
+	}
+	return doc.renameSecurityRequirements(schemeRenames)
+}
+
+// renameComponentRefs rewrites the references to the components of the
+// document which differ from the merged ones with the same names, and
+// returns the renamed references. As rewriting the references may make
+// the other components differ, like a list of the renamed component,
+// the components are compared again until no more component is renamed.
+func (m *merger) renameComponentRefs(i int, doc *Document, src, dst reflect.Value) (map[string]string, error) {
+	refRenames := map[string]string{}
+	for {
+		renames := map[string]string{}
+		for f := 0; f < src.NumField(); f++ {
+			if src.Type().Field(f).PkgPath != "" {
+				continue
+			}
+			kind := yamlFieldName(src.Type().Field(f))
+			srcMap, dstMap := src.Field(f), dst.Field(f)
+			for _, name := range sortedMapKeys(srcMap.Interface()) {
+				key := reflect.ValueOf(name)
+				ref := componentRef(kind, name)
+				if _, ok := refRenames[ref]; ok || dstMap.IsNil() {
+					continue
+				}
+				existing := dstMap.MapIndex(key)
+				if existing.IsValid() && !reflect.DeepEqual(existing.Interface(), srcMap.MapIndex(key).Interface()) {
+					renames[ref] = componentRef(kind, m.prefix(i, doc)+name)
+				}
+			}
+		}
+		if len(renames) == 0 {
+			return refRenames, nil
+		}
+		err := doc.rewriteRefs(func(ref string) string {
+			if renamed, ok := renames[ref]; ok {
+				return renamed
+			}
+			return ref
+		})
+		if err != nil {
+			return nil, err
+		}
+		for ref, renamed := range renames {
+			refRenames[ref] = renamed
+		}
+	}
+}
+
+func componentRef(kind, name string) string {
+	return "#/components/" + kind + "/" + jsonPointerEscaper.Replace(name)
+}
+
+// yamlFieldName returns the key of the field in YAML.
+func yamlFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func equalSecurityRequirements(a, b []*SecurityRequirement) bool {
+	if len(a) != len(b) || (a == nil) != (b == nil) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i].mp, b[i].mp) {
+			return false
+		}
+	}
+	return true
+}
+
+// pushDownServers copies the servers of the document into the path items
+// which do not have their own servers.
+func pushDownServers(doc *Document) {
+	if len(doc.Servers) == 0 {
+		return
+	}
+	for _, pathItem := range doc.Paths {
+		if pathItem != nil && len(pathItem.Servers) == 0 {
+			pathItem.Servers = doc.Servers
+		}
+	}
+}
+
+// pushDownSecurity copies the security requirements of the document into
+// the operations which do not have their own security requirements.
+func pushDownSecurity(doc *Document) {
+	if doc.Security == nil {
+		return
+	}
+	for _, pathItem := range doc.Paths {
+		if pathItem == nil {
+			continue
+		}
+		for _, op := range pathItem.Operations() {
+			if op.Security == nil {
+				security := doc.Security
+				op.Security = &security
+			}
+		}
+	}
+}
+
+func (m *merger) mergePaths(doc *Document) {
+	for _, path := range sortedMapKeys(doc.Paths) {
+		src := doc.Paths[path]
+		dst, ok := m.merged.Paths[path]
+		if !ok || dst == nil {
+			m.merged.Paths[path] = src
+			continue
+		}
+		if src == nil {
+			continue
+		}
+		if !reflect.DeepEqual(dst.Parameters, src.Parameters) || !reflect.DeepEqual(dst.Servers, src.Servers) || dst.Ref != src.Ref {
+			m.conflict("paths." + path)
+			continue
+		}
+		for _, method := range methods {
+			op := src.GetOperationByMethod(method)
+			if op == nil {
+				continue
+			}
+			if dst.GetOperationByMethod(method) != nil {
+				m.conflict("paths." + path + "." + strings.ToLower(method))
+				continue
+			}
+			dst.setOperation(method, op)
+		}
+	}
+}
+
+func (m *merger) mergeTags(doc *Document) {
+	for _, tag := range doc.Tags {
+		if tag == nil {
+			continue
+		}
+		var found bool
+		for _, t := range m.merged.Tags {
+			if t.Name == tag.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.merged.Tags = append(m.merged.Tags, tag)
+		}
+	}
+}
+
+func (m *merger) checkOperationIDs() {
+	seen := map[string]bool{}
+	m.merged.Walk(func(doc *Document, method, path string, pathItem *PathItem, op *Operation) error {
+		if op.OperationID == "" {
+			return nil
+		}
+		if seen[op.OperationID] {
+			m.conflict("operationId " + op.OperationID)
+		}
+		seen[op.OperationID] = true
+		return nil
+	})
+}
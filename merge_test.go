@@ -0,0 +1,322 @@
+package openapi_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const mergePetSpec = `
+openapi: 3.0.2
+info:
+  title: pets
+  version: 1.0.0
+servers:
+  - url: https://pets.example.com
+security:
+  - auth: []
+tags:
+  - name: pets
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Item'
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        name:
+          type: string
+  responses:
+    Error:
+      description: error
+  securitySchemes:
+    auth:
+      type: http
+      scheme: bearer
+`
+
+const mergeStoreSpec = `
+openapi: 3.0.2
+info:
+  title: store
+  version: 1.0.0
+servers:
+  - url: https://store.example.com
+security:
+  - auth: []
+tags:
+  - name: pets
+  - name: store
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [store]
+      responses:
+        '200':
+          description: orders
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Item'
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        price:
+          type: integer
+  responses:
+    Error:
+      description: error
+  securitySchemes:
+    auth:
+      type: http
+      scheme: basic
+`
+
+func loadMergeSpecs(t *testing.T, specs ...string) []*openapi.Document {
+	var docs []*openapi.Document
+	for _, spec := range specs {
+		doc, err := openapi.Load([]byte(spec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	candidates := []struct {
+		label    string
+		strategy openapi.ConflictStrategy
+		specs    []string
+		expected []string
+	}{
+		{
+			"error",
+			openapi.ConflictError,
+			[]string{mergePetSpec, mergeStoreSpec},
+			[]string{"components.schemas.Item", "components.responses.Error", "components.securitySchemes.auth"},
+		},
+		{
+			"dedupe",
+			openapi.ConflictDedupe,
+			[]string{mergePetSpec, mergeStoreSpec},
+			[]string{"components.schemas.Item", "components.securitySchemes.auth"},
+		},
+		{
+			"duplicatedOperation",
+			openapi.ConflictDedupe,
+			[]string{mergePetSpec, mergePetSpec},
+			[]string{"paths./pets.get"},
+		},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			_, err := openapi.MergeOptions{Strategy: c.strategy}.Merge(loadMergeSpecs(t, c.specs...)...)
+			expected := openapi.ErrMergeConflict{Conflicts: c.expected}
+			if !reflect.DeepEqual(err, expected) {
+				t.Errorf("%v != %v", err, expected)
+			}
+		})
+	}
+}
+
+func TestMerge_DuplicatedOperationID(t *testing.T) {
+	docs := loadMergeSpecs(t, mergePetSpec)
+	other, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: other
+  version: 1.0.0
+paths:
+  /animals:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: pets
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = openapi.Merge(docs[0], other)
+	expected := openapi.ErrMergeConflict{Conflicts: []string{"operationId listPets"}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("%v != %v", err, expected)
+	}
+}
+
+func TestMerge_Rename(t *testing.T) {
+	docs := loadMergeSpecs(t, mergePetSpec, mergeStoreSpec)
+	merged, err := openapi.MergeOptions{Strategy: openapi.ConflictRename}.Merge(docs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := merged.Components.Schemas
+	if schemas["Item"].Properties["name"] == nil || schemas["store_Item"].Properties["price"] == nil {
+		t.Errorf("unexpected schemas: %+v", schemas)
+	}
+	if _, ok := merged.Components.Responses["store_Error"]; ok {
+		t.Error("identical response should be deduplicated")
+	}
+	items := merged.Paths["/orders"].Get.Responses["200"].Content["application/json"].Schema.Items
+	if items.Ref != "#/components/schemas/store_Item" {
+		t.Errorf("reference is not rewritten: %s", items.Ref)
+	}
+	if docs[1].Paths["/orders"].Get.Responses["200"].Content["application/json"].Schema.Items.Ref != "#/components/schemas/Item" {
+		t.Error("original document should not be modified")
+	}
+
+	// servers and security differ, so they are pushed down
+	if merged.Servers != nil || merged.Security != nil {
+		t.Errorf("unexpected servers or security: %+v %+v", merged.Servers, merged.Security)
+	}
+	if got := merged.Paths["/orders"].Servers[0].URL; got != "https://store.example.com" {
+		t.Errorf("%s != https://store.example.com", got)
+	}
+	if got := (*merged.Paths["/orders"].Get.Security)[0].Names(); !reflect.DeepEqual(got, []string{"store_auth"}) {
+		t.Errorf("%v != [store_auth]", got)
+	}
+	if got := (*merged.Paths["/pets"].Get.Security)[0].Names(); !reflect.DeepEqual(got, []string{"auth"}) {
+		t.Errorf("%v != [auth]", got)
+	}
+
+	var tags []string
+	for _, tag := range merged.Tags {
+		tags = append(tags, tag.Name)
+	}
+	if !reflect.DeepEqual(tags, []string{"pets", "store"}) {
+		t.Errorf("%v != [pets store]", tags)
+	}
+}
+
+func TestMerge_EmptySecurity(t *testing.T) {
+	docs := loadMergeSpecs(t, mergePetSpec, `
+openapi: 3.0.2
+info:
+  title: health
+  version: 1.0.0
+security:
+  - auth: []
+paths:
+  /health:
+    get:
+      operationId: health
+      security: []
+      responses:
+        '204':
+          description: healthy
+components:
+  securitySchemes:
+    auth:
+      type: http
+      scheme: basic
+`)
+	merged, err := openapi.MergeOptions{Strategy: openapi.ConflictRename}.Merge(docs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the public operation should not be protected by the pushed down security
+	if security := merged.Paths["/health"].Get.Security; security == nil || len(*security) != 0 {
+		t.Errorf("unexpected security: %+v", security)
+	}
+}
+
+func TestMerge_RenameReferring(t *testing.T) {
+	spec := `
+openapi: 3.0.2
+info:
+  title: %s
+  version: 1.0.0
+paths:
+  /%s:
+    get:
+      operationId: list%s
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/PetList'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        %s:
+          type: string
+    PetList:
+      type: array
+      items:
+        $ref: '#/components/schemas/Pet'
+`
+	docs := loadMergeSpecs(t, fmt.Sprintf(spec, "a", "cats", "Cats", "name"), fmt.Sprintf(spec, "b", "dogs", "Dogs", "breed"))
+	merged, err := openapi.MergeOptions{Strategy: openapi.ConflictRename}.Merge(docs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// PetList of b looks identical before Pet is renamed, but it refers b_Pet
+	schemas := merged.Components.Schemas
+	if list := schemas["b_PetList"]; list == nil || list.Items.Ref != "#/components/schemas/b_Pet" {
+		t.Errorf("unexpected b_PetList: %+v", list)
+	}
+	if ref := schemas["PetList"].Items.Ref; ref != "#/components/schemas/Pet" {
+		t.Errorf("%s != #/components/schemas/Pet", ref)
+	}
+	if ref := merged.Paths["/dogs"].Get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/b_PetList" {
+		t.Errorf("%s != #/components/schemas/b_PetList", ref)
+	}
+}
+
+func TestMerge_CommonServers(t *testing.T) {
+	docs := loadMergeSpecs(t, mergePetSpec, mergePetSpec)
+	docs[1].Paths["/animals"] = docs[1].Paths["/pets"]
+	delete(docs[1].Paths, "/pets")
+	docs[1].Paths["/animals"].Get.OperationID = "listAnimals"
+	merged, err := openapi.MergeOptions{Strategy: openapi.ConflictDedupe}.Merge(docs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Servers) != 1 || len(merged.Security) != 1 {
+		t.Errorf("common servers and security should be kept: %+v %+v", merged.Servers, merged.Security)
+	}
+	if merged.Paths["/animals"].Servers != nil {
+		t.Error("common servers should not be pushed down")
+	}
+}
+
+func TestMerge_NoDocuments(t *testing.T) {
+	_, err := openapi.Merge()
+	if err != (openapi.ErrRequired{Target: "documents"}) {
+		t.Errorf("%v != %v", err, openapi.ErrRequired{Target: "documents"})
+	}
+}
@@ -0,0 +1,304 @@
+package openapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ExampleValue generates an example value which conforms to the schema.
+// The example, the default value or the first enum value of the schema
+// is used if it is set, otherwise the value is generated from the type,
+// the constraints and the example of the registered format. The write only
+// properties are omitted, as the value is an example of the responses.
+// root is used to resolve references, and can be nil if the schema
+// does not contain any reference.
+func (schema *Schema) ExampleValue(root *Document) (interface{}, error) {
+	return schema.exampleValue(root, map[*Schema]bool{})
+}
+
+func (schema *Schema) exampleValue(root *Document, visiting map[*Schema]bool) (interface{}, error) {
+	schema, err := resolveSchema(root, schema)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil || visiting[schema] {
+		// recursive schemas end with null
+		return nil, nil
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	switch {
+	case schema.Example != nil:
+		return yamlToJSONValue(schema.Example), nil
+	case schema.Default != nil:
+		return yamlToJSONValue(schema.Default), nil
+	case len(schema.Enum) > 0:
+		return yamlToJSONValue(schema.Enum[0]), nil
+	case len(schema.OneOf) > 0:
+		return schema.OneOf[0].exampleValue(root, visiting)
+	case len(schema.AnyOf) > 0:
+		return schema.AnyOf[0].exampleValue(root, visiting)
+	}
+	switch schema.Type {
+	case "string":
+		return schema.stringExample(), nil
+	case "integer":
+		return int64(schema.numberExample()), nil
+	case "number":
+		return schema.numberExample(), nil
+	case "boolean":
+		return true, nil
+	case "array":
+		return schema.arrayExample(root, visiting)
+	}
+	return schema.objectExample(root, visiting)
+}
+
+func (schema *Schema) stringExample() string {
+	s := "string"
+	if format, ok := LookupFormat(schema.Format); ok && format.Example != "" {
+		s = format.Example
+	}
+	if len(s) < schema.MinLength {
+		s += strings.Repeat("x", schema.MinLength-len(s))
+	}
+	if schema.MaxLength != nil && *schema.MaxLength < len(s) {
+		s = s[:*schema.MaxLength]
+	}
+	return s
+}
+
+func (schema *Schema) numberExample() float64 {
+	step := big.NewRat(1, 1)
+	multipleOf := schema.MultipleOf.rat()
+	if multipleOf != nil && multipleOf.Sign() > 0 {
+		step = multipleOf
+	} else {
+		multipleOf = nil
+	}
+	n := new(big.Rat)
+	if min := schema.Minimum.rat(); min != nil {
+		n.Set(min)
+		if schema.ExclusiveMinimum {
+			n.Add(n, step)
+		}
+	}
+	max := schema.Maximum.rat()
+	if max != nil && (n.Cmp(max) > 0 || schema.ExclusiveMaximum && n.Cmp(max) == 0) {
+		n.Set(max)
+		if schema.ExclusiveMaximum {
+			n.Sub(n, step)
+		}
+	}
+	if multipleOf != nil {
+		// round up to the multiple, or round down if it exceeds the maximum
+		q := new(big.Rat).Quo(n, multipleOf)
+		k := new(big.Int).Div(q.Num(), q.Denom())
+		if !q.IsInt() {
+			k.Add(k, big.NewInt(1))
+		}
+		m := new(big.Rat).Mul(new(big.Rat).SetInt(k), multipleOf)
+		if max != nil && (m.Cmp(max) > 0 || schema.ExclusiveMaximum && m.Cmp(max) == 0) {
+			m.Sub(m, multipleOf)
+		}
+		n = m
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+func (schema *Schema) arrayExample(root *Document, visiting map[*Schema]bool) (interface{}, error) {
+	items := []interface{}{}
+	if schema.Items == nil {
+		return items, nil
+	}
+	item, err := schema.Items.exampleValue(root, visiting)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return items, nil
+	}
+	n := schema.MinItems
+	if n == 0 {
+		n = 1
+	}
+	if schema.MaxItems != nil && *schema.MaxItems < n {
+		n = *schema.MaxItems
+	}
+	for i := 0; i < n; i++ {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (schema *Schema) objectExample(root *Document, visiting map[*Schema]bool) (interface{}, error) {
+	obj := map[string]interface{}{}
+	for _, s := range schema.AllOf {
+		v, err := s.exampleValue(root, visiting)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			if len(schema.Properties) == 0 {
+				return v, nil
+			}
+			continue
+		}
+		for k, val := range m {
+			obj[k] = val
+		}
+	}
+	if schema.Type == "" && len(schema.AllOf) > 0 && len(schema.Properties) == 0 {
+		return obj, nil
+	}
+	for _, name := range sortedMapKeys(schema.Properties) {
+		prop, err := resolveSchema(root, schema.Properties[name])
+		if err != nil {
+			return nil, err
+		}
+		if prop == nil || prop.WriteOnly {
+			continue
+		}
+		v, err := prop.exampleValue(root, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil && !containsString(schema.Required, name) {
+			continue
+		}
+		obj[name] = v
+	}
+	return obj, nil
+}
+
+// NewMockHandler returns a http.Handler which responds to the requests with
+// the examples of the operations in the document.
+// The response is the success response of the operation, or the response of
+// the status code given by "Prefer: code=<status>" request header. The body
+// is the example of the media type, which prefers application/json, or the
+// value generated from its schema.
+// The handler responds with 404 Not Found if no path matches the request,
+// and 405 Method Not Allowed if the path has no operation for the method.
+func NewMockHandler(doc *Document) http.Handler {
+	return &mockHandler{doc: doc}
+}
+
+type mockHandler struct {
+	doc *Document
+}
+
+func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, _, _, ok := h.doc.matchPath(r.Method, r.URL)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	op := h.doc.Paths[path].GetOperationByMethod(r.Method)
+	if op == nil {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	response, status, err := h.response(op, r.Header.Get("Prefer"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if response == nil {
+		http.Error(w, "no response is defined", http.StatusNotImplemented)
+		return
+	}
+	mediaType, body, err := h.body(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if mediaType != "" {
+		w.Header().Set("Content-Type", mediaType)
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// response returns the response of the operation and its status code.
+func (h *mockHandler) response(op *Operation, prefer string) (*Response, int, error) {
+	var response *Response
+	var status int
+	if strings.HasPrefix(prefer, "code=") {
+		code := strings.TrimPrefix(prefer, "code=")
+		if r, ok := op.Responses[code]; ok {
+			response = r
+			status, _ = strconv.Atoi(code)
+		}
+	}
+	if response == nil {
+		response, status, _ = op.SuccessResponse()
+	}
+	if status <= 0 {
+		status = http.StatusOK
+	}
+	if response != nil && response.Ref != "" {
+		resolved, err := ResolveResponse(h.doc, response.Ref)
+		if err != nil {
+			return nil, 0, err
+		}
+		response = resolved
+	}
+	return response, status, nil
+}
+
+// body returns the media type and the encoded example of the response.
+func (h *mockHandler) body(response *Response) (string, []byte, error) {
+	if len(response.Content) == 0 {
+		return "", nil, nil
+	}
+	mediaType := "application/json"
+	if _, ok := response.Content[mediaType]; !ok {
+		mediaType = sortedMapKeys(response.Content)[0]
+	}
+	v, err := h.doc.mediaTypeExample(response.Content[mediaType])
+	if err != nil {
+		return "", nil, err
+	}
+	if s, ok := v.(string); ok && !strings.Contains(mediaType, "json") {
+		return mediaType, []byte(s), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", nil, err
+	}
+	return mediaType, b, nil
+}
+
+// mediaTypeExample returns the example of the media type, which is the
+// example, the first of the examples or the value generated from its schema.
+func (doc *Document) mediaTypeExample(mt *MediaType) (interface{}, error) {
+	if mt == nil {
+		return nil, nil
+	}
+	if mt.Example != nil {
+		return yamlToJSONValue(mt.Example), nil
+	}
+	if len(mt.Examples) > 0 {
+		example := mt.Examples[sortedMapKeys(mt.Examples)[0]]
+		if example != nil && example.Ref != "" {
+			resolved, err := ResolveExample(doc, example.Ref)
+			if err != nil {
+				return nil, err
+			}
+			example = resolved
+		}
+		if example != nil && example.Value != nil {
+			return yamlToJSONValue(example.Value), nil
+		}
+	}
+	if mt.Schema == nil {
+		return nil, nil
+	}
+	return mt.Schema.ExampleValue(doc)
+}
@@ -0,0 +1,192 @@
+package openapi_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const mockSpec = `
+openapi: 3.0.2
+info:
+  title: mock
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                minItems: 2
+                items:
+                  $ref: '#/components/schemas/Pet'
+        '404':
+          $ref: '#/components/responses/NotFound'
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              examples:
+                cat:
+                  value: {name: Tama}
+    delete:
+      operationId: deletePet
+      responses:
+        '204':
+          description: deleted
+components:
+  responses:
+    NotFound:
+      description: not found
+      content:
+        text/plain:
+          example: pet is not found
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          minimum: 10
+          exclusiveMinimum: true
+        name:
+          type: string
+          minLength: 8
+        kind:
+          type: string
+          enum: [cat, dog]
+        birthday:
+          type: string
+          format: date
+        password:
+          type: string
+          writeOnly: true
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestSchema_ExampleValue(t *testing.T) {
+	doc, err := openapi.Load([]byte(mockSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := []struct {
+		label    string
+		schema   *openapi.Schema
+		expected interface{}
+	}{
+		{"example", &openapi.Schema{Type: "string", Example: "foo"}, "foo"},
+		{"default", &openapi.Schema{Type: "integer", Default: 3}, 3},
+		{"enum", &openapi.Schema{Type: "boolean", Enum: []interface{}{false}}, false},
+		{"string", &openapi.Schema{Type: "string", MaxLength: intPtr(3)}, "str"},
+		{"format", &openapi.Schema{Type: "string", Format: "email"}, "user@example.com"},
+		{"integer", &openapi.Schema{Type: "integer", Minimum: "3", MultipleOf: "5"}, int64(5)},
+		{"maximum", &openapi.Schema{Type: "number", Maximum: "-2", ExclusiveMaximum: true}, float64(-3)},
+		{"decimal", &openapi.Schema{Type: "number", Minimum: "0.5", ExclusiveMinimum: true, MultipleOf: "0.25"}, 0.75},
+		{"multipleOfMaximum", &openapi.Schema{Type: "number", Minimum: "0.3", Maximum: "0.35", MultipleOf: "0.1"}, 0.3},
+		{"array", &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "boolean"}}, []interface{}{true}},
+		{"oneOf", &openapi.Schema{OneOf: []*openapi.Schema{{Type: "integer"}, {Type: "string"}}}, int64(0)},
+		{"allOf", &openapi.Schema{AllOf: []*openapi.Schema{
+			{Type: "object", Properties: map[string]*openapi.Schema{"a": {Type: "integer"}}},
+			{Type: "object", Properties: map[string]*openapi.Schema{"b": {Type: "boolean"}}},
+		}}, map[string]interface{}{"a": int64(0), "b": true}},
+		{"ref", &openapi.Schema{Ref: "#/components/schemas/Pet"}, map[string]interface{}{
+			"id":       int64(11),
+			"name":     "stringxx",
+			"kind":     "cat",
+			"birthday": "2019-01-01",
+		}},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			v, err := c.schema.ExampleValue(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, c.expected) {
+				t.Errorf("%#v != %#v", v, c.expected)
+			}
+			if err := c.schema.ValidateValue(doc, v); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMockHandler(t *testing.T) {
+	doc, err := openapi.Load([]byte(mockSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(openapi.NewMockHandler(doc))
+	defer server.Close()
+
+	candidates := []struct {
+		label       string
+		method      string
+		path        string
+		prefer      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"schema", http.MethodGet, "/v1/pets", "", 200, "application/json", `[{"birthday":"2019-01-01","id":11,"kind":"cat","name":"stringxx"},{"birthday":"2019-01-01","id":11,"kind":"cat","name":"stringxx"}]`},
+		{"examples", http.MethodGet, "/v1/pets/1", "", 200, "application/json", `{"name":"Tama"}`},
+		{"prefer", http.MethodGet, "/v1/pets", "code=404", 404, "text/plain", "pet is not found"},
+		{"noContent", http.MethodDelete, "/v1/pets/1", "", 204, "", ""},
+		{"notFound", http.MethodGet, "/v1/owners", "", 404, "text/plain; charset=utf-8", "404 page not found\n"},
+		{"methodNotAllowed", http.MethodPost, "/v1/pets", "", 405, "text/plain; charset=utf-8", "Method Not Allowed\n"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			req, err := http.NewRequest(c.method, server.URL+c.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.prefer != "" {
+				req.Header.Set("Prefer", c.prefer)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != c.status {
+				t.Errorf("%d != %d", resp.StatusCode, c.status)
+			}
+			if got := resp.Header.Get("Content-Type"); got != c.contentType {
+				t.Errorf("%s != %s", got, c.contentType)
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != c.body {
+				t.Errorf("%s != %s", body, c.body)
+			}
+		})
+	}
+}
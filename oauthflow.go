@@ -11,10 +11,10 @@ import (
 // OAuthFlow Object
 type OAuthFlow struct {
 	flowType         string
-	AuthorizationURL string `yaml:"authorizationUrl"`
-	TokenURL         string `yaml:"tokenUrl"`
-	RefreshURL       string `yaml:"refreshUrl"`
-	Scopes           map[string]string
+	AuthorizationURL string            `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `yaml:"tokenUrl,omitempty"`
+	RefreshURL       string            `yaml:"refreshUrl,omitempty"`
+	Scopes           map[string]string `yaml:"scopes,omitempty"`
 }
 
 var defined = struct{}{}
@@ -2,6 +2,8 @@ package openapi
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 
@@ -38,15 +40,93 @@ func Load(b []byte) (*Document, error) {
 	if doc.Servers == nil || len(doc.Servers) == 0 {
 		doc.Servers = []*Server{&Server{URL: "/"}}
 	}
+	doc.linkSecurityRequirements()
+	return doc, nil
+}
+
+// linkSecurityRequirements sets the document to the security requirements
+// in it, which is needed to validate them.
+func (doc *Document) linkSecurityRequirements() {
 	for i := range doc.Security {
 		doc.Security[i].setDocument(doc)
 	}
 	for _, pi := range doc.Paths {
+		if pi == nil {
+			continue
+		}
 		for _, op := range pi.Operations() {
-			for _, sr := range op.Security {
+			for _, sr := range op.securityRequirements() {
 				sr.setDocument(doc)
 			}
 		}
 	}
+}
+
+// MarshalJSON implements json.Marshaler.
+// The document is serialized with the same field names as YAML.
+func (doc *Document) MarshalJSON() ([]byte, error) {
+	v, err := doc.toValue()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// toValue converts the document into a generic value whose objects are
+// map[string]interface{} and arrays are []interface{}.
+func (doc *Document) toValue() (interface{}, error) {
+	return toGenericValue(doc)
+}
+
+// toGenericValue converts the object of the document into a generic value.
+func toGenericValue(obj interface{}) (interface{}, error) {
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return yamlToJSONValue(v), nil
+}
+
+// documentFromValue converts the generic value into a document.
+func documentFromValue(v interface{}) (*Document, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+	doc.linkSecurityRequirements()
 	return doc, nil
 }
+
+// yamlToJSONValue converts the maps in a value decoded by yaml into
+// map[string]interface{}, so that the value can be encoded as JSON.
+func yamlToJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = yamlToJSONValue(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[k] = yamlToJSONValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = yamlToJSONValue(val)
+		}
+		return s
+	}
+	return v
+}
@@ -2,49 +2,56 @@ package openapi
 
 import (
 	"strconv"
+	"strings"
 )
 
 // codebeat:disable[TOO_MANY_IVARS]
 
 // Operation Object
 type Operation struct {
-	Tags         []string
-	Summary      string                 `yaml:"summary"`
-	Description  string                 `yaml:"description"`
-	ExternalDocs *ExternalDocumentation `yaml:"externalDocs"`
-	OperationID  string                 `yaml:"operationId"`
-	Parameters   []*Parameter           `yaml:"parameters"`
-	RequestBody  *RequestBody           `yaml:"requestBody"`
-	Responses    Responses              `yaml:"responses"`
-	Callbacks    map[string]*Callback
-	Deprecated   bool
-	Security     []*SecurityRequirement
-	Servers      []*Server
+	Tags         []string               `yaml:"tags,omitempty"`
+	Summary      string                 `yaml:"summary,omitempty"`
+	Description  string                 `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocumentation `yaml:"externalDocs,omitempty"`
+	OperationID  string                 `yaml:"operationId,omitempty"`
+	Parameters   []*Parameter           `yaml:"parameters,omitempty"`
+	RequestBody  *RequestBody           `yaml:"requestBody,omitempty"`
+	Responses    Responses              `yaml:"responses,omitempty"`
+	Callbacks    map[string]*Callback   `yaml:"callbacks,omitempty"`
+	Deprecated   bool                   `yaml:"deprecated,omitempty"`
+	// Security overrides the security requirements of the document if it
+	// is not nil. The empty requirements, "security: []", remove them.
+	Security *[]*SecurityRequirement `yaml:"security,omitempty"`
+	Servers  []*Server               `yaml:"servers,omitempty"`
 	// Extension    interface{} `yaml:"x-apigw"`
-	Extension *XAPIGateway `yaml:"x-apigw"`
+	Extension *XAPIGateway `yaml:"x-apigw,omitempty"`
+
+	// SpecificationExtensions holds the specification extensions other
+	// than x-apigw, whose keys start with "x-".
+	SpecificationExtensions map[string]interface{} `yaml:",inline"`
 }
 
 type XAPIGateway struct {
-	Hosts              []*Hosts        `yaml:"hosts"`
-	RequireAuth        bool            `yaml:"requireAuth"`
-	RatelimitPerMinute int             `yaml:"ratelimitPerMinute"`
-	SpecificRule       []*SpecificRule `yaml:"specificRule"`
+	Hosts              []*Hosts        `yaml:"hosts,omitempty"`
+	RequireAuth        bool            `yaml:"requireAuth,omitempty"`
+	RatelimitPerMinute int             `yaml:"ratelimitPerMinute,omitempty"`
+	SpecificRule       []*SpecificRule `yaml:"specificRule,omitempty"`
 }
 
 type Hosts struct {
-	From string `yaml:"from"`
-	To   *To    `yaml:"to"`
+	From string `yaml:"from,omitempty"`
+	To   *To    `yaml:"to,omitempty"`
 }
 
 type To struct {
-	Protocol string `yaml:"protocol"`
-	Host     string `yaml:"host"`
-	Port     string `yaml:"port"`
+	Protocol string `yaml:"protocol,omitempty"`
+	Host     string `yaml:"host,omitempty"`
+	Port     string `yaml:"port,omitempty"`
 }
 
 type SpecificRule struct {
-	RemoteAddr         string `yaml:"remoteAddr"`
-	RatelimitPerMinute int    `yaml:"ratelimitPerMinute"`
+	RemoteAddr         string `yaml:"remoteAddr,omitempty"`
+	RatelimitPerMinute int    `yaml:"ratelimitPerMinute,omitempty"`
 }
 
 // SuccessResponse returns a success response object.
@@ -96,11 +103,63 @@ func (operation Operation) Validate() error {
 	for _, callback := range operation.Callbacks {
 		validaters = append(validaters, callback)
 	}
-	for _, security := range operation.Security {
+	for _, security := range operation.securityRequirements() {
 		validaters = append(validaters, security)
 	}
 	for _, server := range operation.Servers {
 		validaters = append(validaters, server)
 	}
+	for _, key := range sortedMapKeys(operation.SpecificationExtensions) {
+		if !strings.HasPrefix(key, "x-") {
+			return ErrUnknownField{Object: "operation", Name: key}
+		}
+	}
 	return validateAll(validaters)
 }
+
+// securityRequirements returns the security requirements declared in the
+// operation, or nil if they are not declared.
+func (operation Operation) securityRequirements() []*SecurityRequirement {
+	if operation.Security == nil {
+		return nil
+	}
+	return *operation.Security
+}
+
+// operationParameters returns the parameters for the operation,
+// which are merged with the parameters of the path item.
+// References are resolved, and the parameters in the operation override
+// the ones in the path item which have the same name and location.
+func operationParameters(root *Document, pathItem *PathItem, op *Operation) ([]*Parameter, error) {
+	var params []*Parameter
+	index := map[string]int{}
+	var lists [][]*Parameter
+	if pathItem != nil {
+		lists = append(lists, pathItem.Parameters)
+	}
+	if op != nil {
+		lists = append(lists, op.Parameters)
+	}
+	for _, list := range lists {
+		for _, p := range list {
+			if p == nil {
+				continue
+			}
+			if p.Ref != "" {
+				resolved, err := ResolveParameter(root, p.Ref)
+				if err != nil {
+					return nil, err
+				}
+				p = resolved
+			}
+			key := string(p.In) + "." + p.Name
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+	return params, nil
+}
@@ -3,9 +3,11 @@ package openapi_test
 import (
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
+	yaml "gopkg.in/yaml.v2"
 )
 
 func TestOperation_Validate(t *testing.T) {
@@ -13,6 +15,8 @@ func TestOperation_Validate(t *testing.T) {
 		{"empty", openapi.Operation{}, openapi.ErrRequired{Target: "operation.responses"}},
 		{"duplicatedParameter", openapi.Operation{Responses: openapi.Responses{}, Parameters: []*openapi.Parameter{&openapi.Parameter{Name: "foo", In: "query"}, &openapi.Parameter{Name: "foo", In: "query"}}}, openapi.ErrParameterDuplicated},
 		{"valid", openapi.Operation{Responses: openapi.Responses{}}, nil},
+		{"extension", openapi.Operation{Responses: openapi.Responses{}, SpecificationExtensions: map[string]interface{}{"x-public": true}}, nil},
+		{"unknownField", openapi.Operation{Responses: openapi.Responses{}, SpecificationExtensions: map[string]interface{}{"operationID": "foo"}}, openapi.ErrUnknownField{Object: "operation", Name: "operationID"}},
 	}
 	testValidater(t, candidates)
 }
@@ -58,3 +62,72 @@ func TestOperation_SuccessResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestOperation_MarshalYAML(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: empty security
+  version: 1.0.0
+security:
+  - api_key: []
+paths:
+  /health:
+    get:
+      security: []
+      responses:
+        '204':
+          description: healthy
+      x-empty: []
+components:
+  schemas:
+    Pet:
+      type: string
+      maxLength: 0
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"security: []", "x-empty: []", "maxLength: 0"} {
+		t.Run(strconv.Itoa(i)+"/"+want, func(t *testing.T) {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("%q is not in:\n%s", want, b)
+			}
+		})
+	}
+	reloaded, err := openapi.Load(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	security := reloaded.Paths["/health"].Get.Security
+	if security == nil || len(*security) != 0 {
+		t.Errorf("unexpected security: %#v", security)
+	}
+
+	// the empty security set in code is kept too
+	op := openapi.Operation{Security: &[]*openapi.SecurityRequirement{}}
+	b, err = yaml.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "security: []\n" {
+		t.Errorf("unexpected yaml: %s", b)
+	}
+
+	b, err = yaml.Marshal(openapi.Document{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "paths: {}") {
+		t.Errorf("paths is not in: %s", b)
+	}
+}
@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Overlay is an OpenAPI Overlay document, which describes repeatable
+// modifications to an OpenAPI document.
+type Overlay struct {
+	Overlay string           `yaml:"overlay,omitempty"`
+	Info    *Info            `yaml:"info,omitempty"`
+	Extends string           `yaml:"extends,omitempty"`
+	Actions []*OverlayAction `yaml:"actions,omitempty"`
+}
+
+// OverlayAction is an action of the Overlay. The nodes selected by the
+// JSONPath Target are removed if Remove is true, otherwise updated with
+// Update.
+type OverlayAction struct {
+	Target      string      `yaml:"target,omitempty"`
+	Description string      `yaml:"description,omitempty"`
+	Update      interface{} `yaml:"update,omitempty"`
+	Remove      bool        `yaml:"remove,omitempty"`
+}
+
+// LoadOverlay loads an Overlay document.
+func LoadOverlay(b []byte) (*Overlay, error) {
+	overlay := &Overlay{}
+	if err := yaml.Unmarshal(b, overlay); err != nil {
+		return nil, err
+	}
+	return overlay, nil
+}
+
+// Validate the values of Overlay object.
+func (overlay Overlay) Validate() error {
+	if overlay.Overlay == "" {
+		return ErrRequired{Target: "overlay"}
+	}
+	if overlay.Info == nil {
+		return ErrRequired{Target: "info"}
+	}
+	if len(overlay.Actions) == 0 {
+		return ErrRequired{Target: "actions"}
+	}
+	for _, action := range overlay.Actions {
+		if action.Target == "" {
+			return ErrRequired{Target: "action.target"}
+		}
+		if _, err := parseJSONPath(action.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyOverlay applies the actions of the overlay in order and returns
+// a new document with the actions which matched nothing. The document
+// itself is not modified.
+// An update merges the objects recursively into the selected objects,
+// appends to the selected arrays and replaces the other selected values.
+// A remove deletes the selected values from their parents.
+func (doc *Document) ApplyOverlay(overlay *Overlay) (*Document, []*OverlayAction, error) {
+	if err := overlay.Validate(); err != nil {
+		return nil, nil, err
+	}
+	v, err := doc.toValue()
+	if err != nil {
+		return nil, nil, err
+	}
+	var unmatched []*OverlayAction
+	for _, action := range overlay.Actions {
+		nodes, err := evaluateJSONPath(v, action.Target)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(nodes) == 0 {
+			unmatched = append(unmatched, action)
+			continue
+		}
+		if action.Remove {
+			sortNodesForRemoval(nodes)
+			for _, node := range nodes {
+				if len(node.tokens) == 0 {
+					return nil, nil, ErrFormatInvalid{Target: "remove target " + action.Target}
+				}
+				v, _, err = removeJSONValue(v, joinJSONPointer(node.tokens))
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			continue
+		}
+		update := yamlToJSONValue(action.Update)
+		for _, node := range nodes {
+			v, err = setJSONValue(v, node.tokens, updateJSONValue(node.value, copyJSONValue(update)))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	newDoc, err := documentFromValue(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newDoc, unmatched, nil
+}
+
+// updateJSONValue merges the update into the target value.
+func updateJSONValue(target, update interface{}) interface{} {
+	switch t := target.(type) {
+	case map[string]interface{}:
+		u, ok := update.(map[string]interface{})
+		if !ok {
+			return update
+		}
+		for k, val := range u {
+			if current, ok := t[k]; ok {
+				t[k] = updateJSONValue(current, val)
+				continue
+			}
+			t[k] = val
+		}
+		return t
+	case []interface{}:
+		if u, ok := update.([]interface{}); ok {
+			return append(t, u...)
+		}
+		return append(t, update)
+	}
+	return update
+}
+
+// setJSONValue replaces the value at the location of the reference
+// tokens, and returns the new root value.
+func setJSONValue(v interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return modifyJSONValue(v, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			c[i] = value
+			return c, nil
+		}
+		return nil, ErrFormatInvalid{Target: "path " + token}
+	})
+}
+
+func joinJSONPointer(tokens []string) string {
+	ptr := ""
+	for _, token := range tokens {
+		ptr = childPointer(ptr, token)
+	}
+	return ptr
+}
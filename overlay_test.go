@@ -0,0 +1,122 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const overlaySpec = `
+overlay: 1.0.0
+info:
+  title: vendor fixes
+  version: 1.0.0
+actions:
+  - target: $.info
+    update:
+      description: patched by overlay
+      contact:
+        name: api team
+        url: https://example.com
+  - target: $.paths['/pets'].get
+    update:
+      tags: [pets]
+  - target: $.paths.*.get.tags
+    update: [public]
+  - target: $..[?(@.name == 'limit')]
+    update:
+      description: maximum number of items
+  - target: $.components.schemas['Orphan','OrphanChild']
+    remove: true
+  - target: $.components.securitySchemes[?(@.scheme)]
+    remove: true
+  - target: $.components.schemas.Bird
+    description: matches nothing
+    remove: true
+`
+
+func TestDocument_ApplyOverlay(t *testing.T) {
+	doc, err := openapi.Load([]byte(pruneSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := openapi.LoadOverlay([]byte(overlaySpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, unmatched, err := doc.ApplyOverlay(overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmatched) != 1 || unmatched[0].Target != "$.components.schemas.Bird" {
+		t.Errorf("unexpected unmatched actions: %v", unmatched)
+	}
+	if applied.Info.Description != "patched by overlay" || applied.Info.Title != "prune" || applied.Info.Contact.Name != "api team" {
+		t.Errorf("unexpected info: %+v", applied.Info)
+	}
+	if got := applied.Paths["/pets"].Get.Tags; !reflect.DeepEqual(got, []string{"pets", "public"}) {
+		t.Errorf("%v != [pets public]", got)
+	}
+	if got := applied.Components.Parameters["Limit"].Description; got != "maximum number of items" {
+		t.Errorf("%s != maximum number of items", got)
+	}
+	if got, expected := sortedKeysOf(applied.Components.Schemas), []string{"Cat", "Dog", "Limit", "Pet", "Pets"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("%v != %v", got, expected)
+	}
+	if got := sortedKeysOf(applied.Components.SecuritySchemes); !reflect.DeepEqual(got, []string{"api_key"}) {
+		t.Errorf("%v != [api_key]", got)
+	}
+	if err := applied.Validate(); err != nil {
+		t.Error(err)
+	}
+	if doc.Info.Description != "" || len(doc.Components.Schemas) != 7 {
+		t.Error("original document should not be modified")
+	}
+}
+
+func TestDocument_ApplyOverlay_Remove(t *testing.T) {
+	doc, err := openapi.Load([]byte(filterSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay := &openapi.Overlay{
+		Overlay: "1.0.0",
+		Info:    &openapi.Info{Title: "remove", Version: "1.0.0"},
+		Actions: []*openapi.OverlayAction{
+			{Target: "$.tags[*]", Remove: true},
+			{Target: "$.paths.*.get[?(@ == true)]", Remove: true},
+		},
+	}
+	applied, unmatched, err := doc.ApplyOverlay(overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmatched) != 0 {
+		t.Errorf("unexpected unmatched actions: %v", unmatched)
+	}
+	if len(applied.Tags) != 0 {
+		t.Errorf("tags should be removed: %v", applied.Tags)
+	}
+	if op := applied.Paths["/pets/{id}"].Get; op.Deprecated || op.SpecificationExtensions["x-public"] != nil {
+		t.Errorf("true values should be removed: %+v", op)
+	}
+	if got := applied.Paths["/admin/users"].Get.SpecificationExtensions["x-public"]; got != false {
+		t.Errorf("%v != false", got)
+	}
+}
+
+func TestOverlay_Validate(t *testing.T) {
+	info := &openapi.Info{Title: "overlay", Version: "1.0.0"}
+	candidates := []candidate{
+		{"empty", openapi.Overlay{}, openapi.ErrRequired{Target: "overlay"}},
+		{"noInfo", openapi.Overlay{Overlay: "1.0.0"}, openapi.ErrRequired{Target: "info"}},
+		{"noActions", openapi.Overlay{Overlay: "1.0.0", Info: info}, openapi.ErrRequired{Target: "actions"}},
+		{"noTarget", openapi.Overlay{Overlay: "1.0.0", Info: info, Actions: []*openapi.OverlayAction{{Remove: true}}}, openapi.ErrRequired{Target: "action.target"}},
+		{"notRoot", openapi.Overlay{Overlay: "1.0.0", Info: info, Actions: []*openapi.OverlayAction{{Target: "info", Remove: true}}}, openapi.ErrFormatInvalid{Target: "JSONPath info"}},
+		{"unclosed", openapi.Overlay{Overlay: "1.0.0", Info: info, Actions: []*openapi.OverlayAction{{Target: "$.paths['/pets'", Remove: true}}}, openapi.ErrFormatInvalid{Target: "JSONPath $.paths['/pets'"}},
+		{"invalidFilter", openapi.Overlay{Overlay: "1.0.0", Info: info, Actions: []*openapi.OverlayAction{{Target: "$.tags[?(name)]", Remove: true}}}, openapi.ErrFormatInvalid{Target: "JSONPath $.tags[?(name)]"}},
+		{"valid", openapi.Overlay{Overlay: "1.0.0", Info: info, Actions: []*openapi.OverlayAction{{Target: "$..parameters[0]", Remove: true}}}, nil},
+	}
+	testValidater(t, candidates)
+}
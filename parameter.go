@@ -4,28 +4,32 @@ package openapi
 
 // Parameter Object
 type Parameter struct {
-	Name            string
-	In              InType
-	Description     string
-	Required        bool
-	Deprecated      string
-	AllowEmptyValue bool `yaml:"allowEmptyValue"`
+	Name            string `yaml:"name,omitempty"`
+	In              InType `yaml:"in,omitempty"`
+	Description     string `yaml:"description,omitempty"`
+	Required        bool   `yaml:"required,omitempty"`
+	Deprecated      string `yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool   `yaml:"allowEmptyValue,omitempty"`
 
-	Style         string
-	Explode       bool
-	AllowReserved bool `yaml:"allowReserved"`
-	Schema        *Schema
-	Example       interface{}
-	Examples      map[string]*Example
+	Style         string              `yaml:"style,omitempty"`
+	Explode       *bool               `yaml:"explode,omitempty"`
+	AllowReserved bool                `yaml:"allowReserved,omitempty"`
+	Schema        *Schema             `yaml:"schema,omitempty"`
+	Example       interface{}         `yaml:"example,omitempty"`
+	Examples      map[string]*Example `yaml:"examples,omitempty"`
 
-	Content map[string]*MediaType
+	Content map[string]*MediaType `yaml:"content,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 }
 
 // Validate the values of Parameter object.
-// This function DOES NOT check whether the name field correspond to the associated path or not.
+// This function DOES NOT check whether the name field correspond to the associated path or not,
+// which is checked by Paths.Validate.
 func (parameter Parameter) Validate() error {
+	if parameter.Ref != "" {
+		return nil // validated in doc.Components
+	}
 	if err := parameter.validateRequiredObjects(); err != nil {
 		return err
 	}
@@ -43,6 +47,9 @@ func (parameter Parameter) Validate() error {
 	if len(parameter.Content) > 1 {
 		return ErrTooManyParameterContent
 	}
+	if parameter.Example != nil && len(parameter.Examples) > 0 {
+		return ErrExampleAndExamples
+	}
 
 	return validateAll(parameter.reduceValidaters())
 }
@@ -65,8 +72,11 @@ func (parameter Parameter) reduceValidaters() []validater {
 	if v, ok := parameter.Example.(validater); ok {
 		validaters = append(validaters, v)
 	}
-
-	// example has no validation
+	for _, example := range parameter.Examples {
+		if example != nil {
+			validaters = append(validaters, example)
+		}
+	}
 
 	for _, mediaType := range parameter.Content {
 		validaters = append(validaters, mediaType)
@@ -3,7 +3,7 @@ package openapi_test
 import (
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestHasDuplicatedParameter(t *testing.T) {
@@ -42,6 +42,8 @@ func TestParameter_Validate(t *testing.T) {
 		{"withName-inPath-required", openapi.Parameter{Name: "foo", In: "path", Required: true}, nil},
 		{"allowEmptyValue-notQuery", openapi.Parameter{Name: "foo", In: "header", AllowEmptyValue: true}, openapi.ErrAllowEmptyValueNotValid},
 		{"allowEmptyValue-query", openapi.Parameter{Name: "foo", In: "query", AllowEmptyValue: true}, nil},
+		{"exampleAndExamples", openapi.Parameter{Name: "foo", In: "query", Example: "bar", Examples: map[string]*openapi.Example{"bar": {Value: "bar"}}}, openapi.ErrExampleAndExamples},
+		{"invalidExamples", openapi.Parameter{Name: "foo", In: "query", Examples: map[string]*openapi.Example{"bar": {Value: "bar", ExternalValue: exampleCom}}}, openapi.ErrValueAndExternalValue},
 	}
 	testValidater(t, candidates)
 }
@@ -9,20 +9,20 @@ import (
 
 // PathItem Object
 type PathItem struct {
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 
-	Summary     string
-	Description string
-	Get         *Operation
-	Put         *Operation
-	Post        *Operation
-	Delete      *Operation
-	Options     *Operation
-	Head        *Operation
-	Patch       *Operation
-	Trace       *Operation
-	Servers     []*Server
-	Parameters  []*Parameter
+	Summary     string       `yaml:"summary,omitempty"`
+	Description string       `yaml:"description,omitempty"`
+	Get         *Operation   `yaml:"get,omitempty"`
+	Put         *Operation   `yaml:"put,omitempty"`
+	Post        *Operation   `yaml:"post,omitempty"`
+	Delete      *Operation   `yaml:"delete,omitempty"`
+	Options     *Operation   `yaml:"options,omitempty"`
+	Head        *Operation   `yaml:"head,omitempty"`
+	Patch       *Operation   `yaml:"patch,omitempty"`
+	Trace       *Operation   `yaml:"trace,omitempty"`
+	Servers     []*Server    `yaml:"servers,omitempty"`
+	Parameters  []*Parameter `yaml:"parameters,omitempty"`
 }
 
 var methods = []string{
@@ -62,6 +62,27 @@ func (pathItem *PathItem) GetOperationByMethod(method string) *Operation {
 	}
 }
 
+func (pathItem *PathItem) setOperation(method string, op *Operation) {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		pathItem.Get = op
+	case http.MethodPost:
+		pathItem.Post = op
+	case http.MethodPut:
+		pathItem.Put = op
+	case http.MethodDelete:
+		pathItem.Delete = op
+	case http.MethodOptions:
+		pathItem.Options = op
+	case http.MethodHead:
+		pathItem.Head = op
+	case http.MethodPatch:
+		pathItem.Patch = op
+	case http.MethodTrace:
+		pathItem.Trace = op
+	}
+}
+
 // GetOperationByID returns an operation object which matches given operationId.
 // If the pathItem object has duplicated operationId, this function returns one
 // which match first.
@@ -4,7 +4,7 @@ import (
 	"strconv"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestPathItem_GetOperationByMethod(t *testing.T) {
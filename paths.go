@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -25,7 +26,7 @@ func (paths Paths) Validate() error {
 	if paths.hasDuplicatedPaths() {
 		return ErrPathsDuplicated
 	}
-	return nil
+	return paths.validatePathParameters(nil)
 }
 
 func (paths Paths) hasDuplicatedOperationID() bool {
@@ -93,3 +94,138 @@ func (paths Paths) GetOperationByID(operationID string) *Operation {
 	}
 	return nil
 }
+
+// validatePathParameters validates that every template segment in the
+// paths has a path parameter declared in the path item or the operation,
+// and that every path parameter has a template segment.
+// If root is nil, the parameter references are not resolved, and the
+// missing parameters are reported only if there are no references.
+func (paths Paths) validatePathParameters(root *Document) error {
+	for _, path := range sortedMapKeys(paths) {
+		pathItem := paths[path]
+		if pathItem == nil {
+			continue
+		}
+		names := pathTemplateNames(path)
+		inTemplate := map[string]bool{}
+		for _, name := range names {
+			inTemplate[name] = true
+		}
+		itemParams, itemResolved, err := pathParameterNames(root, pathItem.Parameters)
+		if err != nil {
+			return err
+		}
+		for _, name := range itemParams {
+			if !inTemplate[name] {
+				return ErrPathParameterNotInTemplate{Path: path, Name: name}
+			}
+		}
+		for _, method := range methods {
+			op := pathItem.GetOperationByMethod(method)
+			if op == nil {
+				continue
+			}
+			opParams, opResolved, err := pathParameterNames(root, op.Parameters)
+			if err != nil {
+				return err
+			}
+			declared := map[string]bool{}
+			for _, name := range append(itemParams, opParams...) {
+				if !inTemplate[name] {
+					return ErrPathParameterNotInTemplate{Path: path, Name: name}
+				}
+				declared[name] = true
+			}
+			if !itemResolved || !opResolved {
+				continue
+			}
+			for _, name := range names {
+				if !declared[name] {
+					return ErrPathParameterNotDeclared{Path: path, Name: name}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pathParameterNames returns the names of the path parameters, and
+// reports whether all the references are resolved.
+func pathParameterNames(root *Document, parameters []*Parameter) ([]string, bool, error) {
+	var names []string
+	resolved := true
+	for _, p := range parameters {
+		if p == nil {
+			continue
+		}
+		if p.Ref != "" {
+			if root == nil {
+				resolved = false
+				continue
+			}
+			var err error
+			p, err = ResolveParameter(root, p.Ref)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		if p.In == InPath {
+			names = append(names, p.Name)
+		}
+	}
+	return names, resolved, nil
+}
+
+// pathTemplateNames returns the names of the template segments in the path.
+func pathTemplateNames(path string) []string {
+	var names []string
+	for {
+		ob := strings.IndexByte(path, '{')
+		if ob == -1 {
+			return names
+		}
+		cb := strings.IndexByte(path[ob:], '}')
+		if cb == -1 {
+			return names
+		}
+		names = append(names, path[ob+1:ob+cb])
+		path = path[ob+cb+1:]
+	}
+}
+
+// matchPathTemplate reports whether given path matches the path template,
+// and returns the values of the path parameters.
+func matchPathTemplate(tmpl, path string) (map[string]string, bool) {
+	re, names := compileTemplate(tmpl, func(string) string { return "([^/]+)" }, "")
+	m := re.FindStringSubmatch(path)
+	if m == nil {
+		return nil, false
+	}
+	params := make(map[string]string, len(names))
+	for i, name := range names {
+		params[name] = m[i+1]
+	}
+	return params, true
+}
+
+// findOperationByID returns the path, the method, the path item and
+// the operation which matches given operationId.
+func (paths Paths) findOperationByID(operationID string) (string, string, *PathItem, *Operation, bool) {
+	var pathList []string
+	for path := range paths {
+		pathList = append(pathList, path)
+	}
+	sort.Strings(pathList)
+	for _, path := range pathList {
+		pathItem := paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range methods {
+			if op := pathItem.GetOperationByMethod(method); op != nil && op.OperationID == operationID {
+				return path, method, pathItem, op, true
+			}
+		}
+	}
+	return "", "", nil, nil, false
+}
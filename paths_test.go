@@ -5,7 +5,7 @@ import (
 	"strconv"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -57,6 +57,108 @@ func TestPaths_Validate(t *testing.T) {
 	testValidater(t, candidates)
 }
 
+func TestPaths_Validate_PathParameters(t *testing.T) {
+	responses := openapi.Responses{"200": &openapi.Response{Description: "foo"}}
+	idParam := &openapi.Parameter{Name: "id", In: openapi.InPath, Required: true}
+	candidates := []candidate{
+		{
+			"declaredInPathItem",
+			openapi.Paths{
+				"/foo/{id}": &openapi.PathItem{
+					Parameters: []*openapi.Parameter{idParam},
+					Get:        &openapi.Operation{OperationID: "foo", Responses: responses},
+				},
+			},
+			nil,
+		},
+		{
+			"declaredInOperation",
+			openapi.Paths{
+				"/foo/{id}": &openapi.PathItem{
+					Get: &openapi.Operation{OperationID: "foo", Parameters: []*openapi.Parameter{idParam}, Responses: responses},
+				},
+			},
+			nil,
+		},
+		{
+			"notDeclared",
+			openapi.Paths{
+				"/foo/{id}": &openapi.PathItem{
+					Get: &openapi.Operation{OperationID: "foo", Responses: responses},
+				},
+			},
+			openapi.ErrPathParameterNotDeclared{Path: "/foo/{id}", Name: "id"},
+		},
+		{
+			"notDeclaredInOneOperation",
+			openapi.Paths{
+				"/foo/{id}": &openapi.PathItem{
+					Get:  &openapi.Operation{OperationID: "foo", Parameters: []*openapi.Parameter{idParam}, Responses: responses},
+					Post: &openapi.Operation{OperationID: "bar", Responses: responses},
+				},
+			},
+			openapi.ErrPathParameterNotDeclared{Path: "/foo/{id}", Name: "id"},
+		},
+		{
+			"notInTemplate",
+			openapi.Paths{
+				"/foo": &openapi.PathItem{
+					Get: &openapi.Operation{OperationID: "foo", Parameters: []*openapi.Parameter{idParam}, Responses: responses},
+				},
+			},
+			openapi.ErrPathParameterNotInTemplate{Path: "/foo", Name: "id"},
+		},
+		{
+			"pathItemParameterNotInTemplate",
+			openapi.Paths{
+				"/foo": &openapi.PathItem{
+					Parameters: []*openapi.Parameter{idParam},
+				},
+			},
+			openapi.ErrPathParameterNotInTemplate{Path: "/foo", Name: "id"},
+		},
+		{
+			"unresolvedReference",
+			openapi.Paths{
+				"/foo/{id}": &openapi.PathItem{
+					Get: &openapi.Operation{
+						OperationID: "foo",
+						Parameters:  []*openapi.Parameter{&openapi.Parameter{Ref: "#/components/parameters/id"}},
+						Responses:   responses,
+					},
+				},
+			},
+			nil,
+		},
+	}
+	testValidater(t, candidates)
+}
+
+func TestDocument_Validate_PathParameterReference(t *testing.T) {
+	newDoc := func(paramName string) openapi.Document {
+		return openapi.Document{
+			Version: "3.0.2",
+			Info:    &openapi.Info{Title: "foo", Version: "1.0"},
+			Paths: openapi.Paths{
+				"/foo/{id}": &openapi.PathItem{
+					Parameters: []*openapi.Parameter{&openapi.Parameter{Ref: "#/components/parameters/id"}},
+					Get:        &openapi.Operation{OperationID: "foo", Responses: openapi.Responses{"200": &openapi.Response{Description: "foo"}}},
+				},
+			},
+			Components: &openapi.Components{
+				Parameters: map[string]*openapi.Parameter{
+					"id": &openapi.Parameter{Name: paramName, In: openapi.InPath, Required: true},
+				},
+			},
+		}
+	}
+	candidates := []candidate{
+		{"resolved", newDoc("id"), nil},
+		{"mismatch", newDoc("name"), openapi.ErrPathParameterNotInTemplate{Path: "/foo/{id}", Name: "name"}},
+	}
+	testValidater(t, candidates)
+}
+
 func TestPaths_GetOperationByID(t *testing.T) {
 	target := openapi.Paths{
 		"/": &openapi.PathItem{
@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// componentKinds are the keys of the maps in the components object.
+var componentKinds = []string{
+	string(SchemaComponent),
+	string(ResponseComponent),
+	string(ParameterComponent),
+	string(ExampleComponent),
+	string(RequestBodyComponent),
+	string(HeaderComponent),
+	string(SecuritySchemeComponent),
+	string(LinkComponent),
+	string(CallbackComponent),
+}
+
+// parseComponentRef returns the kind and the name of the component
+// which the local reference points to.
+func parseComponentRef(ref string) (string, string, bool) {
+	const prefix = "#/components/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", "", false
+	}
+	parts := strings.Split(ref[len(prefix):], "/")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], jsonPointerUnescaper.Replace(parts[1]), true
+}
+
+// reachableComponents returns the names of the components which are
+// reachable from the paths and the security requirements of the document,
+// keyed by the kind of the components.
+func (doc *Document) reachableComponents() (map[string]map[string]bool, error) {
+	reachable := map[string]map[string]bool{}
+	for _, kind := range componentKinds {
+		reachable[kind] = map[string]bool{}
+	}
+	var queue [][2]string
+	mark := func(kind, name string) {
+		if names, ok := reachable[kind]; ok && !names[name] {
+			names[name] = true
+			queue = append(queue, [2]string{kind, name})
+		}
+	}
+	markRef := func(ref string) {
+		if kind, name, ok := parseComponentRef(ref); ok {
+			mark(kind, name)
+		}
+	}
+	markSecurity := func(secReqs []*SecurityRequirement) {
+		for _, secReq := range secReqs {
+			if secReq == nil {
+				continue
+			}
+			for _, name := range secReq.Names() {
+				mark("securitySchemes", name)
+			}
+		}
+	}
+
+	seen := map[*Schema]bool{}
+	v := &visitor{
+		fn: func(ptr string, node, parent interface{}) error {
+			if ref := refField(node); ref != nil && *ref != "" {
+				markRef(*ref)
+			}
+			switch n := node.(type) {
+			case *Schema:
+				// the shared schemas mark the same components
+				if seen[n] {
+					return SkipNode
+				}
+				seen[n] = true
+			case *Discriminator:
+				for _, target := range n.Mapping {
+					markRef(mappingRef(target))
+				}
+			case *SecurityRequirement:
+				markSecurity([]*SecurityRequirement{n})
+			}
+			return nil
+		},
+		ancestors: map[*Schema]bool{},
+	}
+	for _, path := range sortedMapKeys(doc.Paths) {
+		if err := v.pathItem(childPointer("/paths", path), doc.Paths[path], doc); err != nil {
+			return nil, err
+		}
+	}
+	markSecurity(doc.Security)
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if err := v.component(doc.Components, c[0], c[1]); err != nil {
+			return nil, err
+		}
+	}
+	return reachable, nil
+}
+
+// component visits the component of the kind which has the name.
+func (v *visitor) component(components *Components, kind, name string) error {
+	if components == nil {
+		return nil
+	}
+	ptr := componentRef(kind, name)[1:]
+	switch kind {
+	case "schemas":
+		return v.schema(ptr, components.Schemas[name], components)
+	case "responses":
+		return v.response(ptr, components.Responses[name], components)
+	case "parameters":
+		return v.parameter(ptr, components.Parameters[name], components)
+	case "examples":
+		if example := components.Examples[name]; example != nil {
+			if err := v.fn(ptr, example, components); err != nil && err != SkipNode {
+				return err
+			}
+		}
+	case "requestBodies":
+		return v.requestBody(ptr, components.RequestBodies[name], components)
+	case "headers":
+		return v.header(ptr, components.Headers[name], components)
+	case "securitySchemes":
+		return v.securityScheme(ptr, components.SecuritySchemes[name], components)
+	case "links":
+		return v.link(ptr, components.Links[name], components)
+	case "callbacks":
+		return v.callback(ptr, components.Callbacks[name], components)
+	}
+	return nil
+}
+
+// UnusedComponents returns the names of the components which are not
+// reachable from the paths and the security requirements of the document,
+// keyed by the kind of the components like "schemas".
+// The references are followed transitively, so a schema which is referred
+// only by an unused schema is also unused. The names are sorted.
+func (doc *Document) UnusedComponents() (map[string][]string, error) {
+	reachable, err := doc.reachableComponents()
+	if err != nil {
+		return nil, err
+	}
+	return doc.unusedComponents(reachable), nil
+}
+
+// PruneComponents removes the unused components from the document and
+// returns the removed component names. See UnusedComponents for details.
+func (doc *Document) PruneComponents() (map[string][]string, error) {
+	reachable, err := doc.reachableComponents()
+	if err != nil {
+		return nil, err
+	}
+	return doc.pruneComponents(reachable), nil
+}
+
+func (doc *Document) unusedComponents(reachable map[string]map[string]bool) map[string][]string {
+	unused := map[string][]string{}
+	if doc.Components == nil {
+		return unused
+	}
+	rv := reflect.ValueOf(doc.Components).Elem()
+	for f := 0; f < rv.NumField(); f++ {
+		if rv.Type().Field(f).PkgPath != "" {
+			continue
+		}
+		kind := yamlFieldName(rv.Type().Field(f))
+		m := rv.Field(f)
+		if m.Len() == 0 {
+			continue
+		}
+		for _, name := range sortedMapKeys(m.Interface()) {
+			if !reachable[kind][name] {
+				unused[kind] = append(unused[kind], name)
+			}
+		}
+	}
+	return unused
+}
+
+// pruneComponents removes the components which are not reachable, and
+// returns the removed component names keyed by the kind.
+func (doc *Document) pruneComponents(reachable map[string]map[string]bool) map[string][]string {
+	unused := doc.unusedComponents(reachable)
+	if len(unused) == 0 {
+		return unused
+	}
+	rv := reflect.ValueOf(doc.Components).Elem()
+	for f := 0; f < rv.NumField(); f++ {
+		if rv.Type().Field(f).PkgPath != "" {
+			continue
+		}
+		kind := yamlFieldName(rv.Type().Field(f))
+		for _, name := range unused[kind] {
+			rv.Field(f).SetMapIndex(reflect.ValueOf(name), reflect.Value{})
+		}
+	}
+	return unused
+}
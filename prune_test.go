@@ -0,0 +1,147 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const pruneSpec = `
+openapi: 3.0.2
+info:
+  title: prune
+  version: 1.0.0
+security:
+  - api_key: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        '200':
+          $ref: '#/components/responses/Pets'
+        default:
+          description: error
+          headers:
+            X-Rate-Limit:
+              $ref: '#/components/headers/RateLimit'
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        $ref: '#/components/schemas/Limit'
+    Unused:
+      name: unused
+      in: query
+  responses:
+    Pets:
+      description: pets
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pets'
+          examples:
+            pets:
+              $ref: '#/components/examples/Pets'
+  headers:
+    RateLimit:
+      schema:
+        type: integer
+    Orphan:
+      schema:
+        type: string
+  examples:
+    Pets:
+      value: []
+    Orphan:
+      value: {}
+  schemas:
+    Limit:
+      type: integer
+    Pets:
+      type: array
+      items:
+        $ref: '#/components/schemas/Pet'
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+      discriminator:
+        propertyName: kind
+        mapping:
+          dog: Dog
+    Cat:
+      type: object
+      required: [kind]
+    Dog:
+      type: object
+      required: [kind]
+    Orphan:
+      type: object
+      properties:
+        child:
+          $ref: '#/components/schemas/OrphanChild'
+    OrphanChild:
+      type: object
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-KEY
+      in: header
+    unused_auth:
+      type: http
+      scheme: basic
+  links:
+    Unused:
+      operationId: listPets
+`
+
+func TestDocument_UnusedComponents(t *testing.T) {
+	doc, err := openapi.Load([]byte(pruneSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"schemas":         []string{"Orphan", "OrphanChild"},
+		"parameters":      []string{"Unused"},
+		"examples":        []string{"Orphan"},
+		"headers":         []string{"Orphan"},
+		"securitySchemes": []string{"unused_auth"},
+		"links":           []string{"Unused"},
+	}
+	unused, err := doc.UnusedComponents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unused, expected) {
+		t.Errorf("%v != %v", unused, expected)
+	}
+	if len(doc.Components.Schemas) != 7 {
+		t.Error("UnusedComponents should not modify the document")
+	}
+
+	removed, err := doc.PruneComponents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(removed, expected) {
+		t.Errorf("%v != %v", removed, expected)
+	}
+	if got := sortedKeysOf(doc.Components.Schemas); !reflect.DeepEqual(got, []string{"Cat", "Dog", "Limit", "Pet", "Pets"}) {
+		t.Errorf("unexpected schemas: %v", got)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+	unused, err = doc.UnusedComponents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unused) != 0 {
+		t.Errorf("no components should be unused: %v", unused)
+	}
+}
@@ -0,0 +1,412 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// codebeat:disable[TOO_MANY_IVARS]
+
+// defaultReferenceSection is the name of the section of the operations
+// which have no tags.
+const defaultReferenceSection = "default"
+
+// reference is the API reference of a document, which is rendered into
+// Markdown or HTML.
+type reference struct {
+	Title        string
+	Version      string
+	Description  string
+	Servers      []*Server
+	ExternalDocs *ExternalDocumentation
+	Sections     []*referenceSection
+}
+
+// referenceSection is a section of the reference for a tag.
+type referenceSection struct {
+	Name         string
+	Anchor       string
+	Description  string
+	ExternalDocs *ExternalDocumentation
+	Operations   []*referenceOperation
+}
+
+type referenceOperation struct {
+	Method       string
+	Path         string
+	Anchor       string
+	OperationID  string
+	Summary      string
+	Description  string
+	Deprecated   bool
+	ExternalDocs *ExternalDocumentation
+	Parameters   []*referenceParameter
+	RequestBody  *referenceRequestBody
+	Responses    []*referenceResponse
+	// Security is the alternatives of the security requirements, like
+	// "api_key" or "oauth (read, write) + api_key".
+	Security []string
+}
+
+type referenceParameter struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Deprecated  bool
+	Description string
+}
+
+type referenceRequestBody struct {
+	Description string
+	Required    bool
+	Contents    []*referenceContent
+}
+
+type referenceResponse struct {
+	Status      string
+	Description string
+	Headers     []*referenceParameter
+	Contents    []*referenceContent
+}
+
+// referenceContent is a media type with its schema as a property tree.
+type referenceContent struct {
+	MediaType  string
+	Type       string
+	Properties []*referenceProperty
+	Example    string
+}
+
+// referenceProperty is a line of a property tree. Depth is the depth of
+// the property from the root of the tree, starting from 0.
+type referenceProperty struct {
+	Name        string
+	Depth       int
+	Type        string
+	Required    bool
+	Description string
+}
+
+// reference builds the reference of the document.
+func (doc *Document) reference() (*reference, error) {
+	ref := &reference{ExternalDocs: doc.ExternalDocs}
+	if doc.Info != nil {
+		ref.Title = doc.Info.Title
+		ref.Version = doc.Info.Version
+		ref.Description = doc.Info.Description
+	}
+	for _, server := range doc.Servers {
+		if server != nil && server.URL != "" && server.URL != "/" {
+			ref.Servers = append(ref.Servers, server)
+		}
+	}
+	sections := map[string]*referenceSection{}
+	var order []string
+	section := func(name string) *referenceSection {
+		if s, ok := sections[name]; ok {
+			return s
+		}
+		s := &referenceSection{Name: name, Anchor: referenceAnchor("tag", name)}
+		sections[name] = s
+		order = append(order, name)
+		return s
+	}
+	// the declared tags come first in order of the declaration
+	for _, tag := range doc.Tags {
+		s := section(tag.Name)
+		s.Description = tag.Description
+		s.ExternalDocs = tag.ExternalDocs
+	}
+	eops, err := doc.EffectiveOperations()
+	if err != nil {
+		return nil, err
+	}
+	for _, eop := range eops {
+		op, err := doc.referenceOperation(eop)
+		if err != nil {
+			return nil, err
+		}
+		tags := eop.Operation.Tags
+		if len(tags) == 0 {
+			tags = []string{defaultReferenceSection}
+		}
+		for _, tag := range tags {
+			s := section(tag)
+			s.Operations = append(s.Operations, op)
+		}
+	}
+	for _, name := range order {
+		if s := sections[name]; len(s.Operations) > 0 {
+			ref.Sections = append(ref.Sections, s)
+		}
+	}
+	return ref, nil
+}
+
+// referenceAnchor returns the anchor of the heading, which contains only
+// lower case letters, digits and hyphens.
+func referenceAnchor(parts ...string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(strings.Join(parts, "-")) {
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteRune('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func (doc *Document) referenceOperation(eop *EffectiveOperation) (*referenceOperation, error) {
+	op := &referenceOperation{
+		Method:       eop.Method,
+		Path:         eop.Path,
+		Anchor:       referenceAnchor("operation", eop.Method, eop.Path),
+		OperationID:  eop.Operation.OperationID,
+		Summary:      eop.Operation.Summary,
+		Description:  eop.Operation.Description,
+		Deprecated:   eop.Operation.Deprecated,
+		ExternalDocs: eop.Operation.ExternalDocs,
+	}
+	for _, p := range eop.Parameters {
+		typ, err := doc.schemaType(p.Schema)
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = append(op.Parameters, &referenceParameter{
+			Name:        p.Name,
+			In:          string(p.In),
+			Type:        typ,
+			Required:    p.Required,
+			Deprecated:  p.Deprecated == "true",
+			Description: p.Description,
+		})
+	}
+	if rb := eop.RequestBody; rb != nil {
+		contents, err := doc.referenceContents(rb.Content)
+		if err != nil {
+			return nil, err
+		}
+		op.RequestBody = &referenceRequestBody{Description: rb.Description, Required: rb.Required, Contents: contents}
+	}
+	for _, status := range sortedMapKeys(eop.Responses) {
+		resp := eop.Responses[status]
+		if resp == nil {
+			continue
+		}
+		contents, err := doc.referenceContents(resp.Content)
+		if err != nil {
+			return nil, err
+		}
+		r := &referenceResponse{Status: status, Description: resp.Description, Contents: contents}
+		for _, name := range sortedMapKeys(resp.Headers) {
+			header := resp.Headers[name]
+			if header == nil {
+				continue
+			}
+			if header.Ref != "" {
+				if header, err = ResolveHeader(doc, header.Ref); err != nil {
+					return nil, err
+				}
+			}
+			typ, err := doc.schemaType(header.Schema)
+			if err != nil {
+				return nil, err
+			}
+			r.Headers = append(r.Headers, &referenceParameter{Name: name, Type: typ, Required: header.Required, Description: header.Description})
+		}
+		op.Responses = append(op.Responses, r)
+	}
+	for _, sr := range eop.Security {
+		var schemes []string
+		for _, name := range sr.Names() {
+			if scopes := sr.Get(name); len(scopes) > 0 {
+				name += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			schemes = append(schemes, name)
+		}
+		if len(schemes) == 0 {
+			schemes = []string{"none"}
+		}
+		op.Security = append(op.Security, strings.Join(schemes, " + "))
+	}
+	return op, nil
+}
+
+func (doc *Document) referenceContents(content map[string]*MediaType) ([]*referenceContent, error) {
+	var contents []*referenceContent
+	for _, mediaType := range sortedMapKeys(content) {
+		mt := content[mediaType]
+		if mt == nil {
+			continue
+		}
+		c := &referenceContent{MediaType: mediaType}
+		if mt.Schema != nil {
+			typ, err := doc.schemaType(mt.Schema)
+			if err != nil {
+				return nil, err
+			}
+			c.Type = typ
+			if c.Properties, err = doc.propertyTree(mt.Schema, 0, map[*Schema]bool{}); err != nil {
+				return nil, err
+			}
+		}
+		example, err := doc.referenceExample(mt)
+		if err != nil {
+			return nil, err
+		}
+		c.Example = example
+		contents = append(contents, c)
+	}
+	return contents, nil
+}
+
+// schemaType returns the type of the schema to show, like "string (date)",
+// "array of Pet" or "Pet", which is the name of the referred schema.
+func (doc *Document) schemaType(schema *Schema) (string, error) {
+	if schema == nil {
+		return "", nil
+	}
+	if schema.Ref != "" {
+		if kind, name, ok := parseComponentRef(schema.Ref); ok && kind == string(SchemaComponent) {
+			return name, nil
+		}
+		resolved, err := resolveSchema(doc, schema)
+		if err != nil {
+			return "", err
+		}
+		return doc.schemaType(resolved)
+	}
+	switch {
+	case schema.Type == "array":
+		items, err := doc.schemaType(schema.Items)
+		if err != nil || items == "" {
+			return "array", err
+		}
+		return "array of " + items, nil
+	case len(schema.OneOf) > 0:
+		return doc.alternativeTypes("one of", schema.OneOf)
+	case len(schema.AnyOf) > 0:
+		return doc.alternativeTypes("any of", schema.AnyOf)
+	case len(schema.AllOf) > 0 && schema.Type == "":
+		return doc.alternativeTypes("all of", schema.AllOf)
+	}
+	typ := schema.Type
+	if typ == "" && len(schema.Properties) > 0 {
+		typ = "object"
+	}
+	if schema.Format != "" {
+		typ += " (" + schema.Format + ")"
+	}
+	if schema.Nullable {
+		typ += ", nullable"
+	}
+	return typ, nil
+}
+
+func (doc *Document) alternativeTypes(label string, schemas []*Schema) (string, error) {
+	types := make([]string, 0, len(schemas))
+	for _, s := range schemas {
+		typ, err := doc.schemaType(s)
+		if err != nil {
+			return "", err
+		}
+		types = append(types, typ)
+	}
+	return label + ": " + strings.Join(types, ", "), nil
+}
+
+// propertyTree returns the properties of the object schema, or the items
+// of the array schema, with the nested properties following each property.
+// The schemas being expanded are not expanded again, so that the tree of
+// a recursive schema ends.
+func (doc *Document) propertyTree(schema *Schema, depth int, visiting map[*Schema]bool) ([]*referenceProperty, error) {
+	schema, err := resolveSchema(doc, schema)
+	if err != nil || schema == nil || visiting[schema] {
+		return nil, err
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	if schema.Type == "array" {
+		return doc.propertyTree(schema.Items, depth, visiting)
+	}
+	var props []*referenceProperty
+	for _, s := range schema.AllOf {
+		sub, err := doc.propertyTree(s, depth, visiting)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, sub...)
+	}
+	for _, name := range sortedMapKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		typ, err := doc.schemaType(prop)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := resolveSchema(doc, prop)
+		if err != nil {
+			return nil, err
+		}
+		description := ""
+		if resolved != nil {
+			description = resolved.Description
+		}
+		props = append(props, &referenceProperty{
+			Name:        name,
+			Depth:       depth,
+			Type:        typ,
+			Required:    containsString(schema.Required, name),
+			Description: description,
+		})
+		children, err := doc.propertyTree(prop, depth+1, visiting)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, children...)
+	}
+	return props, nil
+}
+
+// referenceExample returns the example of the media type formatted as
+// indented JSON. If the media type has no example, it is generated from
+// the schema.
+func (doc *Document) referenceExample(mt *MediaType) (string, error) {
+	v, err := doc.mediaTypeExample(mt)
+	if err != nil || v == nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// referenceIndent returns the indent of the property in the tree.
+func referenceIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+// markdownCell escapes the text to be put in a cell of a Markdown table.
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", "\\|", -1)
+	return strings.Replace(strings.TrimSpace(s), "\n", "<br>", -1)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var referenceFuncs = map[string]interface{}{
+	"cell":   markdownCell,
+	"indent": referenceIndent,
+	"yesNo":  yesNo,
+	"language": func(mediaType string) string {
+		if strings.Contains(mediaType, "json") {
+			return "json"
+		}
+		return ""
+	},
+	"em": func(depth int) string {
+		return strconv.FormatFloat(float64(depth)*1.5, 'f', -1, 64) + "em"
+	},
+}
+
+// WriteMarkdown writes the API reference of the document in Markdown.
+// The operations are grouped into the sections of their tags, and the
+// operations without tags are in the "default" section.
+func (doc *Document) WriteMarkdown(w io.Writer) error {
+	ref, err := doc.reference()
+	if err != nil {
+		return err
+	}
+	return markdownTemplate.Execute(w, ref)
+}
+
+// WriteHTML writes the API reference of the document as a self-contained
+// HTML page, which has no external resources. The sections are same as
+// WriteMarkdown.
+func (doc *Document) WriteHTML(w io.Writer) error {
+	ref, err := doc.reference()
+	if err != nil {
+		return err
+	}
+	return htmlTemplate.Execute(w, ref)
+}
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(referenceFuncs).Parse(`# {{.Title}}{{if .Version}} {{.Version}}{{end}}
+{{if .Description}}
+{{.Description}}
+{{end}}{{if .Servers}}
+Servers:
+{{range .Servers}}
+- ` + "`{{.URL}}`" + `{{if .Description}} {{.Description}}{{end}}{{end}}
+{{end}}{{with .ExternalDocs}}
+{{template "externalDocs" .}}
+{{end}}
+## Contents
+{{range .Sections}}
+- [{{.Name}}](#{{.Anchor}}){{range .Operations}}
+  - [{{.Method}} {{.Path}}](#{{.Anchor}}){{if .Summary}} {{.Summary}}{{end}}{{end}}{{end}}
+{{range .Sections}}
+<a id="{{.Anchor}}"></a>
+
+## {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}{{with .ExternalDocs}}
+{{template "externalDocs" .}}
+{{end}}{{range .Operations}}
+<a id="{{.Anchor}}"></a>
+
+### {{.Method}} {{.Path}}
+{{if .Deprecated}}
+**Deprecated**
+{{end}}{{if .Summary}}
+{{.Summary}}
+{{end}}{{if .Description}}
+{{.Description}}
+{{end}}{{if .OperationID}}
+Operation ID: ` + "`{{.OperationID}}`" + `
+{{end}}{{with .ExternalDocs}}
+{{template "externalDocs" .}}
+{{end}}{{if .Parameters}}
+#### Parameters
+
+| Name | In | Type | Required | Description |
+|------|----|------|----------|-------------|
+{{range .Parameters}}| ` + "`{{.Name}}`" + ` | {{.In}} | {{cell .Type}} | {{yesNo .Required}} | {{if .Deprecated}}**Deprecated** {{end}}{{cell .Description}} |
+{{end}}{{end}}{{with .RequestBody}}
+#### Request body{{if .Required}} (required){{end}}
+{{if .Description}}
+{{.Description}}
+{{end}}{{range .Contents}}{{template "content" .}}{{end}}{{end}}
+#### Responses
+{{range .Responses}}
+##### {{.Status}}
+{{if .Description}}
+{{.Description}}
+{{end}}{{if .Headers}}
+| Header | Type | Required | Description |
+|--------|------|----------|-------------|
+{{range .Headers}}| ` + "`{{.Name}}`" + ` | {{cell .Type}} | {{yesNo .Required}} | {{cell .Description}} |
+{{end}}{{end}}{{range .Contents}}{{template "content" .}}{{end}}{{end}}{{if .Security}}
+#### Security
+{{range .Security}}
+- {{.}}{{end}}
+{{end}}{{end}}{{end}}`))
+
+func init() {
+	template.Must(markdownTemplate.New("externalDocs").Parse(
+		`See also: [{{if .Description}}{{.Description}}{{else}}{{.URL}}{{end}}]({{.URL}})`))
+	template.Must(markdownTemplate.New("content").Parse(`
+` + "`{{.MediaType}}`" + `{{if .Type}}: {{.Type}}{{end}}
+{{if .Properties}}
+{{range .Properties}}{{indent .Depth}}- ` + "`{{.Name}}`" + ` ({{.Type}}{{if .Required}}, required{{end}}){{if .Description}}: {{.Description}}{{end}}
+{{end}}{{end}}{{if .Example}}
+` + "```{{language .MediaType}}" + `
+{{.Example}}
+` + "```" + `
+{{end}}`))
+}
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(referenceFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 0 auto; max-width: 60em; padding: 1em; line-height: 1.5; }
+nav ul { list-style: none; padding-left: 1em; }
+section.operation { border-top: 1px solid #ddd; margin-top: 2em; }
+.method { font-family: monospace; font-weight: bold; text-transform: uppercase; }
+.deprecated { color: #b00; font-weight: bold; }
+.description { white-space: pre-line; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+ul.properties { list-style: none; padding-left: 0; }
+pre { background: #f5f5f5; padding: 0.5em; overflow-x: auto; }
+code { font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}{{if .Version}} <small>{{.Version}}</small>{{end}}</h1>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{if .Servers}}<p>Servers:</p>
+<ul>
+{{range .Servers}}<li><code>{{.URL}}</code>{{if .Description}} {{.Description}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{with .ExternalDocs}}{{template "externalDocs" .}}
+{{end}}<nav>
+<h2>Contents</h2>
+<ul>
+{{range .Sections}}<li><a href="#{{.Anchor}}">{{.Name}}</a>
+<ul>
+{{range .Operations}}<li><a href="#{{.Anchor}}"><span class="method">{{.Method}}</span> {{.Path}}</a>{{if .Summary}} {{.Summary}}{{end}}</li>
+{{end}}</ul>
+</li>
+{{end}}</ul>
+</nav>
+{{range .Sections}}<section id="{{.Anchor}}">
+<h2>{{.Name}}</h2>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{with .ExternalDocs}}{{template "externalDocs" .}}
+{{end}}{{range .Operations}}<section class="operation" id="{{.Anchor}}">
+<h3><span class="method">{{.Method}}</span> <code>{{.Path}}</code></h3>
+{{if .Deprecated}}<p class="deprecated">Deprecated</p>
+{{end}}{{if .Summary}}<p>{{.Summary}}</p>
+{{end}}{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{if .OperationID}}<p>Operation ID: <code>{{.OperationID}}</code></p>
+{{end}}{{with .ExternalDocs}}{{template "externalDocs" .}}
+{{end}}{{if .Parameters}}<h4>Parameters</h4>
+<table>
+<tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{range .Parameters}}<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td>{{.Type}}</td><td>{{yesNo .Required}}</td><td>{{if .Deprecated}}<span class="deprecated">Deprecated</span> {{end}}<span class="description">{{.Description}}</span></td></tr>
+{{end}}</table>
+{{end}}{{with .RequestBody}}<h4>Request body{{if .Required}} (required){{end}}</h4>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{range .Contents}}{{template "content" .}}{{end}}{{end}}<h4>Responses</h4>
+{{range .Responses}}<h5>{{.Status}}</h5>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{if .Headers}}<table>
+<tr><th>Header</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{range .Headers}}<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{yesNo .Required}}</td><td><span class="description">{{.Description}}</span></td></tr>
+{{end}}</table>
+{{end}}{{range .Contents}}{{template "content" .}}{{end}}{{end}}{{if .Security}}<h4>Security</h4>
+<ul>
+{{range .Security}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</section>
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+func init() {
+	htmltemplate.Must(htmlTemplate.New("externalDocs").Parse(
+		`<p>See also: <a href="{{.URL}}">{{if .Description}}{{.Description}}{{else}}{{.URL}}{{end}}</a></p>`))
+	htmltemplate.Must(htmlTemplate.New("content").Parse(`<p><code>{{.MediaType}}</code>{{if .Type}}: {{.Type}}{{end}}</p>
+{{if .Properties}}<ul class="properties">
+{{range .Properties}}<li style="padding-left: {{em .Depth}}"><code>{{.Name}}</code> ({{.Type}}{{if .Required}}, required{{end}}){{if .Description}}: {{.Description}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{if .Example}}<pre><code>{{.Example}}</code></pre>
+{{end}}`))
+}
@@ -0,0 +1,169 @@
+package openapi_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const referenceSpec = `
+openapi: 3.0.2
+info:
+  title: Reference
+  version: 1.0.0
+  description: The pet store.
+externalDocs:
+  url: https://example.com/docs
+tags:
+  - name: pets
+    description: Everything about pets.
+  - name: unused
+security:
+  - api_key: []
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        description: the id | of the pet
+        schema:
+          type: integer
+          format: int64
+    get:
+      operationId: getPet
+      summary: Get a pet
+      tags: [pets]
+      security:
+        - oauth: [read, write]
+          api_key: []
+      responses:
+        '200':
+          description: the pet
+          headers:
+            X-Rate-Limit:
+              required: true
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              example:
+                name: kitty
+                owner:
+                  name: alice
+  /health:
+    get:
+      operationId: health
+      deprecated: true
+      responses:
+        '204':
+          description: healthy
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          description: the name of the pet
+        owner:
+          $ref: '#/components/schemas/Owner'
+        parent:
+          $ref: '#/components/schemas/Pet'
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-Key
+      in: header
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://example.com/oauth
+          scopes:
+            read: read
+            write: write
+`
+
+func TestDocument_WriteMarkdown(t *testing.T) {
+	doc, err := openapi.Load([]byte(referenceSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := doc.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	candidates := []struct {
+		label string
+		want  string
+	}{
+		{"title", "# Reference 1.0.0\n"},
+		{"externalDocs", "See also: [https://example.com/docs](https://example.com/docs)"},
+		{"contents", "- [pets](#tag-pets)\n  - [GET /pets/{id}](#operation-get-pets-id) Get a pet\n- [default](#tag-default)\n  - [GET /health](#operation-get-health)\n"},
+		{"tag", "## pets\n\nEverything about pets.\n"},
+		{"parameter", "| `id` | path | integer (int64) | yes | the id \\| of the pet |\n"},
+		{"header", "| `X-Rate-Limit` | integer | yes |  |\n"},
+		{"schema", "`application/json`: Pet\n"},
+		{"properties", "- `name` (string, required): the name of the pet\n- `owner` (Owner)\n  - `name` (string)\n- `parent` (Pet)\n\n"},
+		{"example", "```json\n{\n  \"name\": \"kitty\",\n  \"owner\": {\n    \"name\": \"alice\"\n  }\n}\n```\n"},
+		{"security", "#### Security\n\n- api_key + oauth (read, write)\n"},
+		{"inheritedSecurity", "### GET /health\n\n**Deprecated**\n\nOperation ID: `health`\n\n#### Responses\n\n##### 204\n\nhealthy\n\n#### Security\n\n- api_key\n"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			if !strings.Contains(md, c.want) {
+				t.Errorf("%q is not in:\n%s", c.want, md)
+			}
+		})
+	}
+	if strings.Contains(md, "unused") {
+		t.Errorf("the tag without operations is in:\n%s", md)
+	}
+}
+
+func TestDocument_WriteHTML(t *testing.T) {
+	doc, err := openapi.Load([]byte(referenceSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := doc.WriteHTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	html := b.String()
+	candidates := []struct {
+		label string
+		want  string
+	}{
+		{"title", "<title>Reference</title>"},
+		{"section", `<section id="tag-pets">`},
+		{"operation", `<section class="operation" id="operation-get-pets-id">`},
+		{"parameter", "<tr><td><code>id</code></td><td>path</td><td>integer (int64)</td><td>yes</td>"},
+		{"nestedProperty", `<li style="padding-left: 1.5em"><code>name</code> (string)</li>`},
+		{"example", "<pre><code>{\n  &#34;name&#34;: &#34;kitty&#34;,"},
+		{"security", "<li>api_key &#43; oauth (read, write)</li>"},
+		{"externalDocs", `<a href="https://example.com/docs">`},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			if !strings.Contains(html, c.want) {
+				t.Errorf("%q is not in:\n%s", c.want, html)
+			}
+		})
+	}
+	if strings.Contains(html, "<link") || strings.Contains(html, "<script") {
+		t.Errorf("the page has external resources:\n%s", html)
+	}
+}
@@ -0,0 +1,301 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaFor returns the schema of the Go type.
+// Named struct types are registered in components.schemas by the type name
+// and a reference to the component is returned. If a schema with the same
+// name is already registered, it is reused, so recursive types are
+// expressed by the references. If the name is used by another type, the
+// name is prefixed with the package name, like "models_Pet".
+//
+// The types which implement json.Marshaler are any values, and the types
+// which implement encoding.TextMarshaler are strings. []byte is a string
+// with byte format, while the byte arrays are arrays of integers.
+//
+// The fields of structs are converted as below:
+//   - the name in json tag is used as the property name, and the field
+//     is skipped if the name is "-"
+//   - the field is required unless it has omitempty option
+//   - pointer fields are nullable
+//   - embedded structs are combined with allOf
+//   - time.Time is a string with date-time format
+//   - description, format, pattern, enum, minimum and maximum tags set the
+//     corresponding fields; enum values are separated by comma
+func (components *Components) SchemaFor(t reflect.Type) (*Schema, error) {
+	return components.schemaFor(t)
+}
+
+// SchemaFor returns a builder of the schema of the Go type, registering
+// the named struct types in components of the document.
+// See Components.SchemaFor for details.
+func (b *DocumentBuilder) SchemaFor(t reflect.Type) (*SchemaBuilder, error) {
+	schema, err := b.components().SchemaFor(t)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaBuilder{schema: schema}, nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (components *Components) schemaFor(t reflect.Type) (*Schema, error) {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case implements(t, jsonMarshalerType):
+		return &Schema{}, nil
+	case implements(t, textMarshalerType):
+		return &Schema{Type: "string"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return components.schemaFor(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := components.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, ErrTypeNotSupported{Type: t.String()}
+		}
+		additional, err := components.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: additional}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return components.structSchema(t)
+		}
+		return components.namedStructSchema(t)
+	}
+	return nil, ErrTypeNotSupported{Type: t.String()}
+}
+
+// implements reports whether the values of the type implement the
+// interface, including the methods of the pointer receivers.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(iface)
+}
+
+func (components *Components) namedStructSchema(t reflect.Type) (*Schema, error) {
+	name, ok := components.schemaTypes[t]
+	if !ok {
+		name = components.schemaName(t)
+	}
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := components.Schemas[name]; ok {
+		return ref, nil
+	}
+	if components.Schemas == nil {
+		components.Schemas = map[string]*Schema{}
+	}
+	if components.schemaTypes == nil {
+		components.schemaTypes = map[reflect.Type]string{}
+	}
+	// register before generating the properties for recursive types
+	registered := &Schema{}
+	components.Schemas[name] = registered
+	components.schemaTypes[t] = name
+	schema, err := components.structSchema(t)
+	if err != nil {
+		delete(components.Schemas, name)
+		delete(components.schemaTypes, t)
+		return nil, err
+	}
+	*registered = *schema
+	return ref, nil
+}
+
+// schemaName returns the component name of the type which is not used by
+// other types.
+func (components *Components) schemaName(t reflect.Type) string {
+	used := make(map[string]bool, len(components.schemaTypes))
+	for _, name := range components.schemaTypes {
+		used[name] = true
+	}
+	name := componentName(t.Name())
+	if !used[name] {
+		return name
+	}
+	name = componentName(path.Base(t.PkgPath())) + "_" + name
+	for i := 2; used[name]; i++ {
+		name = strings.TrimSuffix(name, "_"+strconv.Itoa(i-1)) + "_" + strconv.Itoa(i)
+	}
+	return name
+}
+
+// componentName returns the name replacing the characters which cannot
+// be used in component names.
+func componentName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if mapKeyRegexp.MatchString(string(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func (components *Components) structSchema(t reflect.Type) (*Schema, error) {
+	object := &Schema{Type: "object"}
+	var embedded []*Schema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				schema, err := components.schemaFor(ft)
+				if err != nil {
+					return nil, err
+				}
+				embedded = append(embedded, schema)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema, err := components.fieldSchema(field)
+		if err != nil {
+			return nil, err
+		}
+		if object.Properties == nil {
+			object.Properties = map[string]*Schema{}
+		}
+		object.Properties[name] = schema
+		if !omitempty {
+			object.Required = append(object.Required, name)
+		}
+	}
+	if len(embedded) == 0 {
+		return object, nil
+	}
+	if len(object.Properties) == 0 {
+		if len(embedded) == 1 {
+			return embedded[0], nil
+		}
+		return &Schema{AllOf: embedded}, nil
+	}
+	return &Schema{AllOf: append(embedded, object)}, nil
+}
+
+// jsonFieldName returns the name and omitempty option in json tag of the field.
+func jsonFieldName(field reflect.StructField) (name string, omitempty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return opts[0], omitempty, false
+}
+
+func (components *Components) fieldSchema(field reflect.StructField) (*Schema, error) {
+	schema, err := components.schemaFor(field.Type)
+	if err != nil {
+		return nil, err
+	}
+	description := field.Tag.Get("description")
+	format := field.Tag.Get("format")
+	pattern := field.Tag.Get("pattern")
+	enum := field.Tag.Get("enum")
+	minimum := field.Tag.Get("minimum")
+	maximum := field.Tag.Get("maximum")
+	nullable := field.Type.Kind() == reflect.Ptr
+	if schema.Ref != "" {
+		if description == "" && format == "" && pattern == "" && enum == "" && minimum == "" && maximum == "" && !nullable {
+			return schema, nil
+		}
+		// the siblings of $ref are ignored, so wrap the reference
+		schema = &Schema{AllOf: []*Schema{schema}}
+	}
+	schema.Nullable = nullable
+	schema.Description = description
+	if format != "" {
+		schema.Format = format
+	}
+	schema.Pattern = pattern
+	if enum != "" {
+		for _, e := range strings.Split(enum, ",") {
+			schema.Enum = append(schema.Enum, typedValue(schema.Type, e))
+		}
+	}
+	if minimum != "" {
+		schema.Minimum = Decimal(minimum)
+		if schema.Minimum.rat() == nil {
+			return nil, ErrFormatInvalid{Target: field.Name + " minimum tag", Format: "number"}
+		}
+	}
+	if maximum != "" {
+		schema.Maximum = Decimal(maximum)
+		if schema.Maximum.rat() == nil {
+			return nil, ErrFormatInvalid{Target: field.Name + " maximum tag", Format: "number"}
+		}
+	}
+	return schema, nil
+}
+
+// typedValue converts the string value into the type.
+func typedValue(typ, s string) interface{} {
+	switch typ {
+	case "integer":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return s
+}
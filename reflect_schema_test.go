@@ -0,0 +1,160 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+type reflectBase struct {
+	ID        string    `json:"id" format:"uuid"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type reflectPet struct {
+	reflectBase
+	Name     string            `json:"name" description:"name of the pet"`
+	Kind     string            `json:"kind,omitempty" enum:"cat,dog"`
+	Age      int               `json:"age,omitempty" minimum:"0" maximum:"30"`
+	Tag      *string           `json:"tag,omitempty"`
+	Parent   *reflectPet       `json:"parent,omitempty"`
+	Children []reflectPet      `json:"children,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Photo    []byte            `json:"photo,omitempty"`
+	Checksum [4]byte           `json:"checksum,omitempty"`
+	IP       net.IP            `json:"ip,omitempty"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestComponents_SchemaFor(t *testing.T) {
+	components := &openapi.Components{}
+	schema, err := components.SchemaFor(reflect.TypeOf(reflectPet{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Ref != "#/components/schemas/reflectPet" {
+		t.Fatalf("unexpected ref: %s", schema.Ref)
+	}
+	base := components.Schemas["reflectBase"]
+	if base == nil {
+		t.Fatal("reflectBase is not registered")
+	}
+	if !reflect.DeepEqual(base.Required, []string{"id", "createdAt"}) {
+		t.Errorf("unexpected required: %v", base.Required)
+	}
+	if base.Properties["id"].Format != "uuid" || base.Properties["createdAt"].Format != "date-time" {
+		t.Errorf("unexpected properties: %+v", base.Properties)
+	}
+
+	pet := components.Schemas["reflectPet"]
+	if len(pet.AllOf) != 2 || pet.AllOf[0].Ref != "#/components/schemas/reflectBase" {
+		t.Fatalf("unexpected allOf: %+v", pet.AllOf)
+	}
+	object := pet.AllOf[1]
+	if !reflect.DeepEqual(object.Required, []string{"name"}) {
+		t.Errorf("unexpected required: %v", object.Required)
+	}
+	candidates := []struct {
+		label    string
+		property string
+		expected *openapi.Schema
+	}{
+		{"description", "name", &openapi.Schema{Type: "string", Description: "name of the pet"}},
+		{"enum", "kind", &openapi.Schema{Type: "string", Enum: []interface{}{"cat", "dog"}}},
+		{"minMax", "age", &openapi.Schema{Type: "integer", Format: "int64", Minimum: "0", Maximum: "30"}},
+		{"pointer", "tag", &openapi.Schema{Type: "string", Nullable: true}},
+		{"recursive", "parent", &openapi.Schema{AllOf: []*openapi.Schema{{Ref: "#/components/schemas/reflectPet"}}, Nullable: true}},
+		{"array", "children", &openapi.Schema{Type: "array", Items: &openapi.Schema{Ref: "#/components/schemas/reflectPet"}}},
+		{"map", "labels", &openapi.Schema{Type: "object", AdditionalProperties: &openapi.Schema{Type: "string"}}},
+		{"bytes", "photo", &openapi.Schema{Type: "string", Format: "byte"}},
+		{"byteArray", "checksum", &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "integer", Format: "int32"}}},
+		{"textMarshaler", "ip", &openapi.Schema{Type: "string"}},
+		{"jsonMarshaler", "raw", &openapi.Schema{}},
+		{"ignored", "Ignored", nil},
+		{"unexported", "internal", nil},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			got := object.Properties[c.property]
+			if !reflect.DeepEqual(got, c.expected) {
+				t.Errorf("%+v != %+v", got, c.expected)
+			}
+		})
+	}
+
+	doc := &openapi.Document{
+		Version:    "3.0.2",
+		Info:       &openapi.Info{Title: "foo", Version: "1.0"},
+		Paths:      openapi.Paths{},
+		Components: components,
+	}
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestComponents_SchemaFor_SameName(t *testing.T) {
+	components := &openapi.Components{}
+	if _, err := components.SchemaFor(reflect.TypeOf(reflectBase{})); err != nil {
+		t.Fatal(err)
+	}
+	type reflectBase struct {
+		Name string `json:"name"`
+	}
+	schema, err := components.SchemaFor(reflect.TypeOf(reflectBase{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Ref != "#/components/schemas/go-openapi_test_reflectBase" {
+		t.Errorf("unexpected ref: %s", schema.Ref)
+	}
+	if components.Schemas["go-openapi_test_reflectBase"].Properties["name"] == nil {
+		t.Errorf("unexpected schemas: %+v", components.Schemas)
+	}
+	// the same type is registered once
+	schema, err = components.SchemaFor(reflect.TypeOf(&reflectBase{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Ref != "#/components/schemas/go-openapi_test_reflectBase" || len(components.Schemas) != 2 {
+		t.Errorf("unexpected ref: %s", schema.Ref)
+	}
+}
+
+func TestComponents_SchemaFor_Unsupported(t *testing.T) {
+	components := &openapi.Components{}
+	_, err := components.SchemaFor(reflect.TypeOf(struct{ C chan int }{}))
+	expected := openapi.ErrTypeNotSupported{Type: "chan int"}
+	if err != expected {
+		t.Errorf("%v != %v", err, expected)
+	}
+	_, err = components.SchemaFor(reflect.TypeOf(map[int]string{}))
+	expected = openapi.ErrTypeNotSupported{Type: "map[int]string"}
+	if err != expected {
+		t.Errorf("%v != %v", err, expected)
+	}
+}
+
+func TestDocumentBuilder_SchemaFor(t *testing.T) {
+	b := openapi.NewDocument("foo", "1.0")
+	pet, err := b.SchemaFor(reflect.TypeOf([]reflectPet{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := b.Path("/pets").
+		Get(openapi.NewOperation("listPets").Returns("200", "pets", pet)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Components.Schemas["reflectPet"]; !ok {
+		t.Error("reflectPet is not registered")
+	}
+}
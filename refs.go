@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"reflect"
+)
+
+// rewriteRefs replaces all the references in the document with the
+// values returned by fn, including the values of discriminator.mapping.
+// The objects shared in the document are rewritten once.
+func (doc *Document) rewriteRefs(fn func(ref string) string) error {
+	done := map[interface{}]bool{}
+	return doc.Visit(func(ptr string, node, parent interface{}) error {
+		if ref := refField(node); ref != nil && *ref != "" && !done[ref] {
+			done[ref] = true
+			*ref = fn(*ref)
+		}
+		if d, ok := node.(*Discriminator); ok && !done[d] {
+			done[d] = true
+			for k, target := range d.Mapping {
+				ref := mappingRef(target)
+				if rewritten := fn(ref); rewritten != ref {
+					d.Mapping[k] = rewritten
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// refField returns the pointer to the $ref field of the object,
+// or nil if the object cannot be a reference.
+func refField(node interface{}) *string {
+	switch v := node.(type) {
+	case *Schema:
+		return &v.Ref
+	case *Parameter:
+		return &v.Ref
+	case *Response:
+		return &v.Ref
+	case *RequestBody:
+		return &v.Ref
+	case *Header:
+		return &v.Ref
+	case *Example:
+		return &v.Ref
+	case *Link:
+		return &v.Ref
+	case *SecurityScheme:
+		return &v.Ref
+	case *PathItem:
+		return &v.Ref
+	}
+	return nil
+}
+
+// renameSecurityRequirements renames the security scheme names in the
+// security requirements of the document and its operations.
+func (doc *Document) renameSecurityRequirements(renames map[string]string) error {
+	if len(renames) == 0 {
+		return nil
+	}
+	done := map[*SecurityRequirement]bool{}
+	return doc.Visit(func(ptr string, node, parent interface{}) error {
+		secReq, ok := node.(*SecurityRequirement)
+		if !ok || done[secReq] {
+			return nil
+		}
+		done[secReq] = true
+		mp := make(map[string][]string, len(secReq.mp))
+		for name, scopes := range secReq.mp {
+			if renamed, ok := renames[name]; ok {
+				name = renamed
+			}
+			mp[name] = scopes
+		}
+		secReq.mp = mp
+		return nil
+	})
+}
+
+// clone returns a deep copy of the document. The values are copied as
+// they are, so that the empty values like "security: []" are kept.
+// The objects shared in the document are shared in the copy too.
+func (doc *Document) clone() *Document {
+	copied := map[uintptr]reflect.Value{}
+	cloned := deepCopy(reflect.ValueOf(doc), copied).Interface().(*Document)
+	// the unexported fields are copied shallowly
+	cloned.Visit(func(ptr string, node, parent interface{}) error {
+		if secReq, ok := node.(*SecurityRequirement); ok && secReq.mp != nil {
+			mp := make(map[string][]string, len(secReq.mp))
+			for name, scopes := range secReq.mp {
+				mp[name] = append([]string(nil), scopes...)
+			}
+			secReq.mp = mp
+		}
+		return nil
+	})
+	cloned.linkSecurityRequirements()
+	return cloned
+}
+
+// deepCopy returns a deep copy of the value. The pointers which are
+// already copied are looked up from copied.
+func deepCopy(v reflect.Value, copied map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := copied[v.Pointer()]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		copied[v.Pointer()] = c
+		c.Elem().Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopy(v.MapIndex(k), copied))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			c.Field(i).Set(deepCopy(v.Field(i), copied))
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ComponentKind represents a kind of components, which is the key of
+// the map in components object.
+type ComponentKind string
+
+// ComponentKind list.
+const (
+	SchemaComponent         ComponentKind = "schemas"
+	ResponseComponent       ComponentKind = "responses"
+	ParameterComponent      ComponentKind = "parameters"
+	ExampleComponent        ComponentKind = "examples"
+	RequestBodyComponent    ComponentKind = "requestBodies"
+	HeaderComponent         ComponentKind = "headers"
+	SecuritySchemeComponent ComponentKind = "securitySchemes"
+	LinkComponent           ComponentKind = "links"
+	CallbackComponent       ComponentKind = "callbacks"
+)
+
+// RenameComponent renames the component of the kind from oldName to
+// newName, and rewrites all the references to the component in the
+// document, including the ones into the component like
+// #/components/schemas/Pet/properties/id. If the component is a security
+// scheme, the names in the security requirements are also renamed.
+func (doc *Document) RenameComponent(kind ComponentKind, oldName, newName string) error {
+	if !containsString(componentKinds, string(kind)) {
+		return ErrMustOneOf{Object: "component kind", ValidValues: componentKinds}
+	}
+	if !mapKeyRegexp.MatchString(newName) {
+		return ErrMapKeyFormat
+	}
+	m, ok := doc.componentMap(kind)
+	if !ok || !m.MapIndex(reflect.ValueOf(oldName)).IsValid() {
+		return ErrComponentNotFound{Kind: string(kind), Name: oldName}
+	}
+	if oldName == newName {
+		return nil
+	}
+	if m.MapIndex(reflect.ValueOf(newName)).IsValid() {
+		return ErrComponentExists{Kind: string(kind), Name: newName}
+	}
+	m.SetMapIndex(reflect.ValueOf(newName), m.MapIndex(reflect.ValueOf(oldName)))
+	m.SetMapIndex(reflect.ValueOf(oldName), reflect.Value{})
+
+	oldRef, newRef := componentRef(string(kind), oldName), componentRef(string(kind), newName)
+	err := doc.rewriteRefs(func(ref string) string {
+		if ref == oldRef || strings.HasPrefix(ref, oldRef+"/") {
+			return newRef + strings.TrimPrefix(ref, oldRef)
+		}
+		return ref
+	})
+	if err != nil {
+		return err
+	}
+	if kind == SecuritySchemeComponent {
+		return doc.renameSecurityRequirements(map[string]string{oldName: newName})
+	}
+	return nil
+}
+
+// componentMap returns the map of the components of the kind.
+func (doc *Document) componentMap(kind ComponentKind) (reflect.Value, bool) {
+	if doc.Components == nil {
+		return reflect.Value{}, false
+	}
+	rv := reflect.ValueOf(doc.Components).Elem()
+	for f := 0; f < rv.NumField(); f++ {
+		if rv.Type().Field(f).PkgPath != "" {
+			continue
+		}
+		if yamlFieldName(rv.Type().Field(f)) == string(kind) {
+			return rv.Field(f), true
+		}
+	}
+	return reflect.Value{}, false
+}
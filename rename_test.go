@@ -0,0 +1,116 @@
+package openapi_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestDocument_RenameComponent(t *testing.T) {
+	doc, err := openapi.Load([]byte(pruneSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.RenameComponent(openapi.SchemaComponent, "Pet", "PetV2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.RenameComponent(openapi.SchemaComponent, "Dog", "Hound"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.RenameComponent(openapi.SecuritySchemeComponent, "api_key", "apiKey"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.RenameComponent(openapi.ResponseComponent, "Pets", "PetList"); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := doc.Components.Schemas
+	if _, ok := schemas["Pet"]; ok {
+		t.Error("old name should be removed")
+	}
+	if got := schemas["Pets"].Items.Ref; got != "#/components/schemas/PetV2" {
+		t.Errorf("%s != #/components/schemas/PetV2", got)
+	}
+	if got := schemas["PetV2"].Discriminator.Mapping["dog"]; got != "#/components/schemas/Hound" {
+		t.Errorf("%s != #/components/schemas/Hound", got)
+	}
+	if got := doc.Paths["/pets"].Get.Responses["200"].Ref; got != "#/components/responses/PetList" {
+		t.Errorf("%s != #/components/responses/PetList", got)
+	}
+	if got := doc.Security[0].Names(); !reflect.DeepEqual(got, []string{"apiKey"}) {
+		t.Errorf("%v != [apiKey]", got)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDocument_RenameComponent_SubPointer(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: rename
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    PetV1:
+      type: object
+      properties:
+        id:
+          type: integer
+    PetV10:
+      type: object
+    Order:
+      type: object
+      properties:
+        petId:
+          $ref: '#/components/schemas/PetV1/properties/id'
+        pet:
+          $ref: '#/components/schemas/PetV10'
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.RenameComponent(openapi.SchemaComponent, "PetV1", "Pet"); err != nil {
+		t.Fatal(err)
+	}
+	properties := doc.Components.Schemas["Order"].Properties
+	if got := properties["petId"].Ref; got != "#/components/schemas/Pet/properties/id" {
+		t.Errorf("%s != #/components/schemas/Pet/properties/id", got)
+	}
+	if got := properties["pet"].Ref; got != "#/components/schemas/PetV10" {
+		t.Errorf("%s != #/components/schemas/PetV10", got)
+	}
+}
+
+func TestDocument_RenameComponent_Error(t *testing.T) {
+	candidates := []struct {
+		label   string
+		kind    openapi.ComponentKind
+		oldName string
+		newName string
+		err     error
+	}{
+		{"invalidKind", openapi.ComponentKind("foo"), "Pet", "Cat", openapi.ErrMustOneOf{Object: "component kind", ValidValues: []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "securitySchemes", "links", "callbacks"}}},
+		{"invalidName", openapi.SchemaComponent, "Pet", "Pet V2", openapi.ErrMapKeyFormat},
+		{"notFound", openapi.SchemaComponent, "Bird", "Parrot", openapi.ErrComponentNotFound{Kind: "schemas", Name: "Bird"}},
+		{"emptyKind", openapi.CallbackComponent, "Foo", "Bar", openapi.ErrComponentNotFound{Kind: "callbacks", Name: "Foo"}},
+		{"exists", openapi.SchemaComponent, "Pet", "Cat", openapi.ErrComponentExists{Kind: "schemas", Name: "Cat"}},
+		{"sameName", openapi.SchemaComponent, "Pet", "Pet", nil},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			doc, err := openapi.Load([]byte(pruneSpec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = doc.RenameComponent(c.kind, c.oldName, c.newName)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("%v != %v", err, c.err)
+			}
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package openapi
 
+import (
+	"mime"
+	"sort"
+)
+
 // codebeat:disable[TOO_MANY_IVARS]
 
 // RequestBody Object
 type RequestBody struct {
-	Description string
-	Content     map[string]*MediaType
-	Required    bool
+	Description string                `yaml:"description,omitempty"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+	Required    bool                  `yaml:"required,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 }
 
 // Validate the values of RequestBody object.
@@ -26,3 +31,56 @@ func (requestBody RequestBody) Validate() error {
 	}
 	return nil
 }
+
+// encodeRequestBody validates the body and encodes it for the media type
+// of the request body. application/json is preferred if the request body
+// has two or more media types. If the body is []byte or string, it is
+// used as is without validation.
+// This function returns the content type and the encoded body.
+func encodeRequestBody(root *Document, requestBody *RequestBody, body interface{}) (string, []byte, error) {
+	if requestBody == nil {
+		return "", nil, nil
+	}
+	if requestBody.Ref != "" {
+		resolved, err := ResolveRequestBody(root, requestBody.Ref)
+		if err != nil {
+			return "", nil, err
+		}
+		requestBody = resolved
+	}
+	if body == nil {
+		if requestBody.Required {
+			return "", nil, ErrRequired{Target: "request body"}
+		}
+		return "", nil, nil
+	}
+	contentType := "application/json"
+	mediaType, ok := requestBody.Content[contentType]
+	if !ok {
+		var contentTypes []string
+		for ct := range requestBody.Content {
+			contentTypes = append(contentTypes, ct)
+		}
+		sort.Strings(contentTypes)
+		if len(contentTypes) == 0 {
+			return "", nil, ErrRequired{Target: "requestBody.content"}
+		}
+		contentType = contentTypes[0]
+		mediaType = requestBody.Content[contentType]
+	}
+	switch b := body.(type) {
+	case []byte:
+		return contentType, b, nil
+	case string:
+		return contentType, []byte(b), nil
+	}
+	if mediaType == nil {
+		mediaType = &MediaType{}
+	}
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && isJSONMediaType(mt) && mediaType.Schema != nil {
+		if err := mediaType.Schema.ValidateValue(root, body); err != nil {
+			return "", nil, err
+		}
+	}
+	return mediaType.Encode(root, contentType, body)
+}
@@ -2,6 +2,7 @@ package openapi
 
 import (
 	"errors"
+	"net/url"
 	"strings"
 )
 
@@ -9,6 +10,9 @@ import (
 var ErrTypeAssertion = errors.New("type assertion error")
 
 func resolve(root *Document, ref string) (interface{}, error) {
+	if root == nil {
+		return nil, ErrMissingReferenceRoot
+	}
 	switch {
 	case strings.HasPrefix(ref, "#/"):
 		path := strings.Split(ref, "/")
@@ -31,6 +35,9 @@ func (doc *Document) resolve(path []string) (interface{}, error) {
 }
 
 func (components *Components) resolve(path []string) (interface{}, error) {
+	if components == nil {
+		return nil, errors.New("components is not defined")
+	}
 	if len(path) != 2 {
 		return nil, errors.New("cannot resolve")
 	}
@@ -172,3 +179,43 @@ func ResolveCallback(root *Document, ref string) (*Callback, error) {
 	}
 	return nil, ErrTypeAssertion
 }
+
+// ResolveOperation resolves an operation reference string like
+// #/paths/~12.0~1users~1{username}/get, which is used as link.operationRef.
+func ResolveOperation(root *Document, ref string) (*Operation, error) {
+	_, _, _, op, err := resolveOperationRef(root, ref)
+	return op, err
+}
+
+// resolveOperationRef resolves an operation reference string and
+// returns the path, the method, the path item and the operation.
+func resolveOperationRef(root *Document, ref string) (string, string, *PathItem, *Operation, error) {
+	if root == nil {
+		return "", "", nil, nil, ErrMissingReferenceRoot
+	}
+	if !strings.HasPrefix(ref, "#/paths/") {
+		return "", "", nil, nil, errors.New("cannot resolve operation reference: " + ref)
+	}
+	// the fragment is percent-encoded like #/paths/~1pets~1%7Bid%7D/get
+	fragment, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return "", "", nil, nil, errors.New("cannot resolve operation reference: " + ref)
+	}
+	tokens, err := splitJSONPointer(fragment)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	if len(tokens) != 3 {
+		return "", "", nil, nil, errors.New("cannot resolve operation reference: " + ref)
+	}
+	path, method := tokens[1], strings.ToUpper(tokens[2])
+	pathItem, ok := root.Paths[path]
+	if !ok || pathItem == nil {
+		return "", "", nil, nil, ErrOperationNotFound
+	}
+	op := pathItem.GetOperationByMethod(method)
+	if op == nil {
+		return "", "", nil, nil, ErrOperationNotFound
+	}
+	return path, method, pathItem, op, nil
+}
@@ -4,7 +4,7 @@ import (
 	"reflect"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 )
 
 func TestResolveSchema(t *testing.T) {
@@ -4,12 +4,12 @@ package openapi
 
 // Response Object
 type Response struct {
-	Description string
-	Headers     map[string]*Header
-	Content     map[string]*MediaType
-	Links       map[string]*Link
+	Description string                `yaml:"description,omitempty"`
+	Headers     map[string]*Header    `yaml:"headers,omitempty"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+	Links       map[string]*Link      `yaml:"links,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 }
 
 // Validate the value of Response object.
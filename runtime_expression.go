@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RuntimeExpressionType represents the root of runtime expression.
+type RuntimeExpressionType string
+
+// RuntimeExpressionTypes
+const (
+	URLExpr        RuntimeExpressionType = "$url"
+	MethodExpr     RuntimeExpressionType = "$method"
+	StatusCodeExpr RuntimeExpressionType = "$statusCode"
+	RequestExpr    RuntimeExpressionType = "$request"
+	ResponseExpr   RuntimeExpressionType = "$response"
+)
+
+// RuntimeExpressionSource represents the source part of $request and $response expression.
+type RuntimeExpressionSource string
+
+// RuntimeExpressionSources
+const (
+	HeaderSource RuntimeExpressionSource = "header"
+	QuerySource  RuntimeExpressionSource = "query"
+	PathSource   RuntimeExpressionSource = "path"
+	BodySource   RuntimeExpressionSource = "body"
+)
+
+// RuntimeExpression is a parsed runtime expression.
+// Source and Name are set only when Type is $request or $response,
+// and Pointer is set only when Source is body.
+type RuntimeExpression struct {
+	Type    RuntimeExpressionType
+	Source  RuntimeExpressionSource
+	Name    string
+	Pointer string
+}
+
+// isRuntimeExpression reports whether the string is meant as a runtime
+// expression, which starts with one of the expression types. The other
+// strings starting with "$", like "$5", are constants.
+func isRuntimeExpression(s string) bool {
+	switch RuntimeExpressionType(s) {
+	case URLExpr, MethodExpr, StatusCodeExpr:
+		return true
+	}
+	return strings.HasPrefix(s, string(RequestExpr)+".") || strings.HasPrefix(s, string(ResponseExpr)+".")
+}
+
+// ParseRuntimeExpression parses a runtime expression like $request.body#/id.
+func ParseRuntimeExpression(expr string) (*RuntimeExpression, error) {
+	switch t := RuntimeExpressionType(expr); t {
+	case URLExpr, MethodExpr, StatusCodeExpr:
+		return &RuntimeExpression{Type: t}, nil
+	}
+	var ret RuntimeExpression
+	var source string
+	switch {
+	case strings.HasPrefix(expr, string(RequestExpr)+"."):
+		ret.Type = RequestExpr
+		source = strings.TrimPrefix(expr, string(RequestExpr)+".")
+	case strings.HasPrefix(expr, string(ResponseExpr)+"."):
+		ret.Type = ResponseExpr
+		source = strings.TrimPrefix(expr, string(ResponseExpr)+".")
+	default:
+		return nil, ErrRuntimeExprFormat
+	}
+	switch {
+	case strings.HasPrefix(source, "header."):
+		ret.Source = HeaderSource
+		ret.Name = strings.TrimPrefix(source, "header.")
+		if len(ret.Name) == 0 || len(strings.Trim(ret.Name, rfc7230TChar)) != 0 {
+			return nil, ErrRuntimeExprFormat
+		}
+	case strings.HasPrefix(source, "query."):
+		ret.Source = QuerySource
+		ret.Name = strings.TrimPrefix(source, "query.")
+	case strings.HasPrefix(source, "path."):
+		ret.Source = PathSource
+		ret.Name = strings.TrimPrefix(source, "path.")
+	case source == "body":
+		ret.Source = BodySource
+	case strings.HasPrefix(source, "body#"):
+		ret.Source = BodySource
+		ret.Pointer = strings.TrimPrefix(source, "body#")
+		if !strings.HasPrefix(ret.Pointer, "/") {
+			return nil, ErrRuntimeExprFormat
+		}
+	default:
+		return nil, ErrRuntimeExprFormat
+	}
+	if ret.Source != BodySource && ret.Name == "" {
+		return nil, ErrRuntimeExprFormat
+	}
+	return &ret, nil
+}
+
+// String returns the string representation of the runtime expression.
+func (expr RuntimeExpression) String() string {
+	switch expr.Type {
+	case RequestExpr, ResponseExpr:
+	default:
+		return string(expr.Type)
+	}
+	if expr.Source == BodySource {
+		if expr.Pointer == "" {
+			return string(expr.Type) + ".body"
+		}
+		return string(expr.Type) + ".body#" + expr.Pointer
+	}
+	return string(expr.Type) + "." + string(expr.Source) + "." + expr.Name
+}
+
+// RuntimeContext is a captured request/response pair which
+// runtime expressions are evaluated against.
+// Bodies are given as bytes because http.Request.Body and
+// http.Response.Body can be read only once.
+type RuntimeContext struct {
+	Request      *http.Request
+	RequestBody  []byte
+	PathParams   map[string]string
+	Response     *http.Response
+	ResponseBody []byte
+}
+
+// Evaluate the runtime expression against given context.
+// $statusCode returns int, header, query and path return string,
+// and body returns decoded JSON value.
+func (expr RuntimeExpression) Evaluate(ctx *RuntimeContext) (interface{}, error) {
+	switch expr.Type {
+	case URLExpr:
+		if ctx.Request == nil {
+			return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+		}
+		return requestURL(ctx.Request), nil
+	case MethodExpr:
+		if ctx.Request == nil {
+			return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+		}
+		return ctx.Request.Method, nil
+	case StatusCodeExpr:
+		if ctx.Response == nil {
+			return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+		}
+		return ctx.Response.StatusCode, nil
+	case RequestExpr:
+		if ctx.Request == nil {
+			return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+		}
+		return expr.evaluateSource(ctx.Request.Header, ctx.RequestBody, ctx)
+	case ResponseExpr:
+		if ctx.Response == nil {
+			return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+		}
+		if expr.Source == QuerySource || expr.Source == PathSource {
+			return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+		}
+		return expr.evaluateSource(ctx.Response.Header, ctx.ResponseBody, ctx)
+	}
+	return nil, ErrRuntimeExprFormat
+}
+
+func (expr RuntimeExpression) evaluateSource(header http.Header, body []byte, ctx *RuntimeContext) (interface{}, error) {
+	switch expr.Source {
+	case HeaderSource:
+		if values, ok := header[http.CanonicalHeaderKey(expr.Name)]; ok && len(values) > 0 {
+			return values[0], nil
+		}
+	case QuerySource:
+		if values, ok := ctx.Request.URL.Query()[expr.Name]; ok && len(values) > 0 {
+			return values[0], nil
+		}
+	case PathSource:
+		if v, ok := ctx.PathParams[expr.Name]; ok {
+			return v, nil
+		}
+	case BodySource:
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			if expr.Pointer == "" {
+				return string(body), nil
+			}
+			return nil, err
+		}
+		if v, err := evaluateJSONPointer(v, expr.Pointer); err == nil {
+			return v, nil
+		}
+	}
+	return nil, ErrRuntimeExprNotFound{Expr: expr.String()}
+}
+
+// requestURL returns the full URL of the request.
+// On server side, http.Request.URL does not contain the scheme and the host,
+// so they are completed from the request.
+func requestURL(req *http.Request) string {
+	u := *req.URL
+	if u.Host == "" {
+		u.Host = req.Host
+	}
+	if u.Scheme == "" && u.Host != "" {
+		u.Scheme = "http"
+		if req.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return u.String()
+}
+
+// EvaluateRuntimeExpression evaluates a string which is a runtime expression
+// or a constant value. If the string starts with "$", it is treated as an expression.
+func EvaluateRuntimeExpression(s string, ctx *RuntimeContext) (interface{}, error) {
+	if !strings.HasPrefix(s, "$") {
+		return s, nil
+	}
+	expr, err := ParseRuntimeExpression(s)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Evaluate(ctx)
+}
+
+// ExpandRuntimeExpression substitutes the runtime expressions embedded in
+// curly braces in the string, like callback keys
+// (e.g. http://example.com/{$request.body#/id}).
+func ExpandRuntimeExpression(s string, ctx *RuntimeContext) (string, error) {
+	var buf strings.Builder
+	for {
+		ob := strings.IndexRune(s, '{')
+		if ob == -1 {
+			break
+		}
+		cb := strings.IndexRune(s[ob:], '}')
+		if cb == -1 {
+			break
+		}
+		expr, err := ParseRuntimeExpression(s[ob+1 : ob+cb])
+		if err != nil {
+			return "", err
+		}
+		v, err := expr.Evaluate(ctx)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(s[:ob])
+		buf.WriteString(stringifyValue(v))
+		s = s[ob+cb+1:]
+	}
+	buf.WriteString(s)
+	return buf.String(), nil
+}
+
+// stringifyValue returns a string representation of the value.
+// Composite values are formatted as JSON.
+func stringifyValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err == nil {
+			return string(b)
+		}
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
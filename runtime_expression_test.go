@@ -0,0 +1,124 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestParseRuntimeExpression(t *testing.T) {
+	candidates := []struct {
+		in     string
+		expect *openapi.RuntimeExpression
+		err    error
+	}{
+		{"$url", &openapi.RuntimeExpression{Type: openapi.URLExpr}, nil},
+		{"$method", &openapi.RuntimeExpression{Type: openapi.MethodExpr}, nil},
+		{"$statusCode", &openapi.RuntimeExpression{Type: openapi.StatusCodeExpr}, nil},
+		{"$request.header.accept", &openapi.RuntimeExpression{Type: openapi.RequestExpr, Source: openapi.HeaderSource, Name: "accept"}, nil},
+		{"$request.path.id", &openapi.RuntimeExpression{Type: openapi.RequestExpr, Source: openapi.PathSource, Name: "id"}, nil},
+		{"$request.query.queryUrl", &openapi.RuntimeExpression{Type: openapi.RequestExpr, Source: openapi.QuerySource, Name: "queryUrl"}, nil},
+		{"$request.body#/user/uuid", &openapi.RuntimeExpression{Type: openapi.RequestExpr, Source: openapi.BodySource, Pointer: "/user/uuid"}, nil},
+		{"$response.body", &openapi.RuntimeExpression{Type: openapi.ResponseExpr, Source: openapi.BodySource}, nil},
+		{"$response.header.Location", &openapi.RuntimeExpression{Type: openapi.ResponseExpr, Source: openapi.HeaderSource, Name: "Location"}, nil},
+		{"", nil, openapi.ErrRuntimeExprFormat},
+		{"$foo", nil, openapi.ErrRuntimeExprFormat},
+		{"$request.", nil, openapi.ErrRuntimeExprFormat},
+		{"$request.header.", nil, openapi.ErrRuntimeExprFormat},
+		{"$request.header.a b", nil, openapi.ErrRuntimeExprFormat},
+		{"$request.query.", nil, openapi.ErrRuntimeExprFormat},
+		{"$request.body#", nil, openapi.ErrRuntimeExprFormat},
+		{"$request.cookie.id", nil, openapi.ErrRuntimeExprFormat},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.in, func(t *testing.T) {
+			expr, err := openapi.ParseRuntimeExpression(c.in)
+			if err != c.err {
+				t.Errorf("error should be %v, but %v", c.err, err)
+				return
+			}
+			if !reflect.DeepEqual(expr, c.expect) {
+				t.Errorf("%+v != %+v", expr, c.expect)
+				return
+			}
+			if expr != nil && expr.String() != c.in {
+				t.Errorf("%s != %s", expr.String(), c.in)
+			}
+		})
+	}
+}
+
+func TestRuntimeExpression_Evaluate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/subscribe/42?queryUrl=https://example.com/cb", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": []string{"/subscriptions/1"}},
+	}
+	ctx := &openapi.RuntimeContext{
+		Request:      req,
+		RequestBody:  []byte(`{"user": {"uuid": "u-1", "tags": ["a", "b"]}, "a/b": 1}`),
+		PathParams:   map[string]string{"id": "42"},
+		Response:     resp,
+		ResponseBody: []byte(`{"id": 1}`),
+	}
+	candidates := []struct {
+		in     string
+		expect interface{}
+		err    error
+	}{
+		{"$url", "http://example.com/subscribe/42?queryUrl=https://example.com/cb", nil},
+		{"$method", "POST", nil},
+		{"$statusCode", 201, nil},
+		{"$request.header.x-request-id", "abc", nil},
+		{"$request.query.queryUrl", "https://example.com/cb", nil},
+		{"$request.path.id", "42", nil},
+		{"$request.body#/user/uuid", "u-1", nil},
+		{"$request.body#/user/tags/1", "b", nil},
+		{"$request.body#/a~1b", 1.0, nil},
+		{"$response.header.Location", "/subscriptions/1", nil},
+		{"$response.body#/id", 1.0, nil},
+		{"$response.body", map[string]interface{}{"id": 1.0}, nil},
+		{"$request.header.X-Missing", nil, openapi.ErrRuntimeExprNotFound{Expr: "$request.header.X-Missing"}},
+		{"$request.body#/user/name", nil, openapi.ErrRuntimeExprNotFound{Expr: "$request.body#/user/name"}},
+		{"$response.path.id", nil, openapi.ErrRuntimeExprNotFound{Expr: "$response.path.id"}},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.in, func(t *testing.T) {
+			expr, err := openapi.ParseRuntimeExpression(c.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := expr.Evaluate(ctx)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %v, but %v", c.err, err)
+				return
+			}
+			if !reflect.DeepEqual(v, c.expect) {
+				t.Errorf("%#v != %#v", v, c.expect)
+			}
+		})
+	}
+}
+
+func TestExpandRuntimeExpression(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/subscribe?queryUrl=https://example.com/cb", nil)
+	ctx := &openapi.RuntimeContext{
+		Request:     req,
+		RequestBody: []byte(`{"id": 7}`),
+	}
+	got, err := openapi.ExpandRuntimeExpression("{$request.query.queryUrl}/items/{$request.body#/id}?m={$method}", ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "https://example.com/cb/items/7?m=POST"; got != expect {
+		t.Errorf("%s != %s", got, expect)
+	}
+	if _, err := openapi.ExpandRuntimeExpression("{$foo}", ctx); err != openapi.ErrRuntimeExprFormat {
+		t.Errorf("error should be %v, but %v", openapi.ErrRuntimeExprFormat, err)
+	}
+}
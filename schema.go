@@ -9,44 +9,44 @@ import (
 
 // Schema Object
 type Schema struct {
-	Title            string
-	MultipleOf       int `yaml:"multipleOf"`
-	Maximum          int
-	ExclusiveMaximum bool `yaml:"exclusiveMaximum"`
-	Minimum          int
-	ExclusiveMinimum bool `yaml:"exclusiveMinimum"`
-	MaxLength        int  `yaml:"maxLength"`
-	MinLength        int  `yaml:"minLength"`
-	Pattern          string
-	MaxItems         int `yaml:"maxItems"`
-	MinItems         int `yaml:"minItems"`
-	MaxProperties    int `yaml:"maxProperties"`
-	MinProperties    int `yaml:"minProperties"`
-	Required         []string
-	Enum             []string
+	Title            string        `yaml:"title,omitempty"`
+	MultipleOf       Decimal       `yaml:"multipleOf,omitempty"`
+	Maximum          Decimal       `yaml:"maximum,omitempty"`
+	ExclusiveMaximum bool          `yaml:"exclusiveMaximum,omitempty"`
+	Minimum          Decimal       `yaml:"minimum,omitempty"`
+	ExclusiveMinimum bool          `yaml:"exclusiveMinimum,omitempty"`
+	MaxLength        *int          `yaml:"maxLength,omitempty"`
+	MinLength        int           `yaml:"minLength,omitempty"`
+	Pattern          string        `yaml:"pattern,omitempty"`
+	MaxItems         *int          `yaml:"maxItems,omitempty"`
+	MinItems         int           `yaml:"minItems,omitempty"`
+	MaxProperties    *int          `yaml:"maxProperties,omitempty"`
+	MinProperties    int           `yaml:"minProperties,omitempty"`
+	Required         []string      `yaml:"required,omitempty"`
+	Enum             []interface{} `yaml:"enum,omitempty"`
 
-	Type                 string
-	AllOf                []*Schema `yaml:"allOf"`
-	OneOf                []*Schema `yaml:"oneOf"`
-	AnyOf                []*Schema `yaml:"anyOf"`
-	Not                  *Schema
-	Items                *Schema
-	Properties           map[string]*Schema
-	AdditionalProperties *Schema `yaml:"additionalProperties"`
-	Description          string
-	Format               string
-	Default              string
+	Type                 string             `yaml:"type,omitempty"`
+	AllOf                []*Schema          `yaml:"allOf,omitempty"`
+	OneOf                []*Schema          `yaml:"oneOf,omitempty"`
+	AnyOf                []*Schema          `yaml:"anyOf,omitempty"`
+	Not                  *Schema            `yaml:"not,omitempty"`
+	Items                *Schema            `yaml:"items,omitempty"`
+	Properties           map[string]*Schema `yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty"`
+	Description          string             `yaml:"description,omitempty"`
+	Format               string             `yaml:"format,omitempty"`
+	Default              interface{}        `yaml:"default,omitempty"`
 
-	Nullable      bool
-	Discriminator *Discriminator
-	ReadOnly      bool `yaml:"readOnly"`
-	WriteOnly     bool `yaml:"writeOnly"`
-	XML           *XML
-	ExternalDocs  *ExternalDocumentation `yaml:"externalDocs"`
-	Example       interface{}
-	Deprecated    bool
+	Nullable      bool                   `yaml:"nullable,omitempty"`
+	Discriminator *Discriminator         `yaml:"discriminator,omitempty"`
+	ReadOnly      bool                   `yaml:"readOnly,omitempty"`
+	WriteOnly     bool                   `yaml:"writeOnly,omitempty"`
+	XML           *XML                   `yaml:"xml,omitempty"`
+	ExternalDocs  *ExternalDocumentation `yaml:"externalDocs,omitempty"`
+	Example       interface{}            `yaml:"example,omitempty"`
+	Deprecated    bool                   `yaml:"deprecated,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 
 	Extension map[string]interface{} `yaml:",inline"`
 }
@@ -84,6 +84,9 @@ func (schema Schema) Validate() error {
 	if e, ok := schema.Example.(validater); ok {
 		validaters = append(validaters, e)
 	}
+	if err := schema.validateDecimals(); err != nil {
+		return err
+	}
 	for k := range schema.Extension {
 		if !strings.HasPrefix(k, "x-") {
 			return fmt.Errorf("unknown field: %s", k)
@@ -91,3 +94,23 @@ func (schema Schema) Validate() error {
 	}
 	return validateAll(validaters)
 }
+
+func (schema Schema) validateDecimals() error {
+	decimals := []struct {
+		name  string
+		value Decimal
+	}{
+		{"schema.multipleOf", schema.MultipleOf},
+		{"schema.maximum", schema.Maximum},
+		{"schema.minimum", schema.Minimum},
+	}
+	for _, d := range decimals {
+		if d.value.IsSet() && d.value.rat() == nil {
+			return ErrFormatInvalid{Target: d.name, Format: "number"}
+		}
+	}
+	if schema.MultipleOf.IsSet() && schema.MultipleOf.rat().Sign() <= 0 {
+		return ErrMultipleOfNotPositive
+	}
+	return nil
+}
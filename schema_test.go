@@ -5,13 +5,16 @@ import (
 	"strconv"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 	yaml "gopkg.in/yaml.v2"
 )
 
 func TestSchema_Validate(t *testing.T) {
 	candidates := []candidate{
 		{"empty", openapi.Schema{}, nil},
+		{"decimals", openapi.Schema{Minimum: "0.5", Maximum: "1e3", MultipleOf: "0.01"}, nil},
+		{"invalidMinimum", openapi.Schema{Minimum: "one"}, openapi.ErrFormatInvalid{Target: "schema.minimum", Format: "number"}},
+		{"zeroMultipleOf", openapi.Schema{MultipleOf: "0"}, openapi.ErrMultipleOfNotPositive},
 	}
 	testValidater(t, candidates)
 }
@@ -0,0 +1,435 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrValueInvalid is returned when a value does not conform to the schema.
+// Pointer is a JSON pointer to the invalid value from the root of the
+// validated value.
+type ErrValueInvalid struct {
+	Pointer string
+	Reason  string
+}
+
+func (vie ErrValueInvalid) Error() string {
+	if vie.Pointer == "" {
+		return fmt.Sprintf("value is invalid: %s", vie.Reason)
+	}
+	return fmt.Sprintf("value at %s is invalid: %s", vie.Pointer, vie.Reason)
+}
+
+// ValidateValue validates given value conforms to the schema.
+// The value should be a decoded JSON value: nil, bool, string, numbers,
+// []interface{} or map[string]interface{}. Other types are converted
+// via encoding/json before validation.
+// root is used to resolve references, and can be nil if the schema
+// does not contain any reference.
+func (schema *Schema) ValidateValue(root *Document, value interface{}) error {
+	v, err := normalizeValue(value)
+	if err != nil {
+		return err
+	}
+	return schema.validateValue(root, v, "")
+}
+
+// normalizeValue converts given value into a decoded JSON value.
+func normalizeValue(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case nil, bool, string, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return value, nil
+	case []interface{}, map[string]interface{}:
+		if isJSONValue(value) {
+			return value, nil
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func isJSONValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, bool, string, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if !isJSONValue(item) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		for _, item := range v {
+			if !isJSONValue(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// toFloat returns the numeric value as float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float(), true
+	}
+	return 0, false
+}
+
+// resolveSchema returns the schema referred by schema.ref if it is set.
+// ErrReferenceCycle is returned if the references refer each other.
+func resolveSchema(root *Document, schema *Schema) (*Schema, error) {
+	var refs []string
+	for schema != nil && schema.Ref != "" {
+		if containsString(refs, schema.Ref) {
+			return nil, ErrReferenceCycle{Ref: schema.Ref}
+		}
+		refs = append(refs, schema.Ref)
+		resolved, err := ResolveSchema(root, schema.Ref)
+		if err != nil {
+			return nil, err
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+func (schema *Schema) validateValue(root *Document, value interface{}, ptr string) error {
+	schema, err := resolveSchema(root, schema)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return ErrValueInvalid{Pointer: ptr, Reason: "must not be null"}
+	}
+	if err := schema.validateType(value, ptr); err != nil {
+		return err
+	}
+	if err := schema.validateEnum(value, ptr); err != nil {
+		return err
+	}
+	if err := schema.validateFormat(value, ptr); err != nil {
+		return err
+	}
+	if err := schema.validateComposition(root, value, ptr); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case string:
+		return schema.validateString(v, ptr)
+	case []interface{}:
+		return schema.validateArray(root, v, ptr)
+	case map[string]interface{}:
+		return schema.validateObject(root, v, ptr)
+	}
+	if r, ok := toRat(value); ok {
+		return schema.validateNumber(r, ptr)
+	}
+	return nil
+}
+
+func (schema *Schema) validateType(value interface{}, ptr string) error {
+	var ok bool
+	switch schema.Type {
+	case "":
+		return nil
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "array":
+		_, ok = value.([]interface{})
+	case "object":
+		_, ok = value.(map[string]interface{})
+	case "number":
+		_, ok = toFloat(value)
+	case "integer":
+		var f float64
+		if f, ok = toFloat(value); ok {
+			ok = f == math.Trunc(f)
+		}
+	}
+	if !ok {
+		return ErrValueInvalid{Pointer: ptr, Reason: "must be " + schema.Type}
+	}
+	return nil
+}
+
+// validateFormat validates the value with the registered format.
+// The formats which are not registered are ignored.
+func (schema *Schema) validateFormat(value interface{}, ptr string) error {
+	if schema.Format == "" {
+		return nil
+	}
+	format, ok := LookupFormat(schema.Format)
+	if !ok || format.Validate == nil || format.Validate(value) {
+		return nil
+	}
+	return ErrValueInvalid{Pointer: ptr, Reason: "must be in format " + schema.Format}
+}
+
+func (schema *Schema) validateEnum(value interface{}, ptr string) error {
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+	v := normalizeNumbers(value)
+	values := make([]string, len(schema.Enum))
+	for i, e := range schema.Enum {
+		e = yamlToJSONValue(e)
+		if reflect.DeepEqual(normalizeNumbers(e), v) {
+			return nil
+		}
+		values[i] = enumString(e)
+	}
+	return ErrValueInvalid{Pointer: ptr, Reason: "must be one of: " + strings.Join(values, ", ")}
+}
+
+// enumString returns the enum value to show in the error, which is
+// the string itself or the JSON encoding of other values.
+func enumString(e interface{}) string {
+	if s, ok := e.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Sprint(e)
+	}
+	return string(b)
+}
+
+func (schema *Schema) validateComposition(root *Document, value interface{}, ptr string) error {
+	for _, s := range schema.AllOf {
+		if err := s.validateValue(root, value, ptr); err != nil {
+			return err
+		}
+	}
+	if schema.Discriminator != nil && len(schema.OneOf)+len(schema.AnyOf) > 0 {
+		target, err := ResolveDiscriminator(root, schema, value)
+		if err != nil {
+			if vie, ok := err.(ErrValueInvalid); ok {
+				vie.Pointer = ptr
+				return vie
+			}
+			return err
+		}
+		if err := target.validateValue(root, value, ptr); err != nil {
+			return err
+		}
+	} else if err := schema.validateAlternatives(root, value, ptr); err != nil {
+		return err
+	}
+	if schema.Not != nil && schema.Not.validateValue(root, value, ptr) == nil {
+		return ErrValueInvalid{Pointer: ptr, Reason: "must not match the not schema"}
+	}
+	return nil
+}
+
+// validateAlternatives validates the value against anyOf and oneOf schemas.
+func (schema *Schema) validateAlternatives(root *Document, value interface{}, ptr string) error {
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for _, s := range schema.AnyOf {
+			if s.validateValue(root, value, ptr) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return ErrValueInvalid{Pointer: ptr, Reason: "must match any of anyOf schemas"}
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for _, s := range schema.OneOf {
+			if s.validateValue(root, value, ptr) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return ErrValueInvalid{Pointer: ptr, Reason: "must match exactly one of oneOf schemas"}
+		}
+	}
+	return nil
+}
+
+func (schema *Schema) validateString(s string, ptr string) error {
+	length := len([]rune(s))
+	if schema.MinLength > 0 && length < schema.MinLength {
+		return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("length must be >= %d", schema.MinLength)}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("length must be <= %d", *schema.MaxLength)}
+	}
+	if schema.Pattern != "" {
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return ErrFormatInvalid{Target: "schema.pattern", Format: "regular expression"}
+		}
+		if !re.MatchString(s) {
+			return ErrValueInvalid{Pointer: ptr, Reason: "must match pattern " + schema.Pattern}
+		}
+	}
+	return nil
+}
+
+// validateNumber validates numeric constraints. The constraints are
+// compared as decimals, so that 0.3 is a multiple of 0.1. The constraints
+// which are not numbers are ignored, as they are reported by Validate.
+func (schema *Schema) validateNumber(r *big.Rat, ptr string) error {
+	if min := schema.Minimum.rat(); min != nil {
+		if c := r.Cmp(min); c < 0 || (schema.ExclusiveMinimum && c == 0) {
+			return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must be %s %s", comparator(">", schema.ExclusiveMinimum), schema.Minimum)}
+		}
+	}
+	if max := schema.Maximum.rat(); max != nil {
+		if c := r.Cmp(max); c > 0 || (schema.ExclusiveMaximum && c == 0) {
+			return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must be %s %s", comparator("<", schema.ExclusiveMaximum), schema.Maximum)}
+		}
+	}
+	if m := schema.MultipleOf.rat(); m != nil && m.Sign() > 0 {
+		if q := new(big.Rat).Quo(r, m); !q.IsInt() {
+			return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must be multiple of %s", schema.MultipleOf)}
+		}
+	}
+	return nil
+}
+
+func comparator(op string, exclusive bool) string {
+	if exclusive {
+		return op
+	}
+	return op + "="
+}
+
+func (schema *Schema) validateArray(root *Document, items []interface{}, ptr string) error {
+	if schema.MinItems > 0 && len(items) < schema.MinItems {
+		return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must have >= %d items", schema.MinItems)}
+	}
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must have <= %d items", *schema.MaxItems)}
+	}
+	if schema.Items == nil {
+		return nil
+	}
+	for i, item := range items {
+		if err := schema.Items.validateValue(root, item, ptr+"/"+strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func (schema *Schema) validateObject(root *Document, obj map[string]interface{}, ptr string) error {
+	if schema.MinProperties > 0 && len(obj) < schema.MinProperties {
+		return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must have >= %d properties", schema.MinProperties)}
+	}
+	if schema.MaxProperties != nil && len(obj) > *schema.MaxProperties {
+		return ErrValueInvalid{Pointer: ptr, Reason: fmt.Sprintf("must have <= %d properties", *schema.MaxProperties)}
+	}
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			return ErrValueInvalid{Pointer: ptr, Reason: "missing required property " + name}
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		propSchema, ok := schema.Properties[k]
+		if !ok {
+			propSchema = schema.AdditionalProperties
+		}
+		if err := propSchema.validateValue(root, obj[k], ptr+"/"+jsonPointerEscaper.Replace(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyDefaults returns a copy of the value in which the missing
+// properties are filled with the default values of their schemas.
+// The nested objects and the items of arrays are filled as well.
+// If the value is nil, the default value of the schema is used.
+// The value should be a decoded JSON value as ValidateValue.
+func (schema *Schema) ApplyDefaults(root *Document, value interface{}) (interface{}, error) {
+	v, err := normalizeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return schema.applyDefaults(root, copyJSONValue(v))
+}
+
+func (schema *Schema) applyDefaults(root *Document, value interface{}) (interface{}, error) {
+	schema, err := resolveSchema(root, schema)
+	if err != nil || schema == nil {
+		return value, err
+	}
+	if value == nil {
+		// the default value is filled with the defaults of its properties
+		if value = yamlToJSONValue(schema.Default); value == nil {
+			return nil, nil
+		}
+	}
+	for _, s := range schema.AllOf {
+		if value, err = s.applyDefaults(root, value); err != nil {
+			return nil, err
+		}
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		if schema.Items == nil {
+			return v, nil
+		}
+		for i, item := range v {
+			if v[i], err = schema.Items.applyDefaults(root, item); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range sortedMapKeys(schema.Properties) {
+			prop, ok := v[name]
+			if ok && prop == nil {
+				// explicit null is kept
+				continue
+			}
+			filled, err := schema.Properties[name].applyDefaults(root, prop)
+			if err != nil {
+				return nil, err
+			}
+			if filled != nil {
+				v[name] = filled
+			}
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,165 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestSchema_ValidateValue(t *testing.T) {
+	doc := &openapi.Document{
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Pet": &openapi.Schema{
+					Type:     "object",
+					Required: []string{"name"},
+					Properties: map[string]*openapi.Schema{
+						"name": &openapi.Schema{Type: "string", MinLength: 1},
+						"age":  &openapi.Schema{Type: "integer", Minimum: "1", Maximum: "30"},
+						"tags": &openapi.Schema{Type: "array", MaxItems: intPtr(2), Items: &openapi.Schema{Type: "string"}},
+					},
+				},
+				"A": &openapi.Schema{Ref: "#/components/schemas/B"},
+				"B": &openapi.Schema{Ref: "#/components/schemas/A"},
+			},
+		},
+	}
+	pet := &openapi.Schema{Ref: "#/components/schemas/Pet"}
+	candidates := []struct {
+		label  string
+		schema *openapi.Schema
+		value  interface{}
+		err    error
+	}{
+		{"valid", pet, map[string]interface{}{"name": "tama", "age": 3.0}, nil},
+		{"struct value", pet, struct {
+			Name string `json:"name"`
+		}{"tama"}, nil},
+		{"missing required", pet, map[string]interface{}{"age": 3}, openapi.ErrValueInvalid{Reason: "missing required property name"}},
+		{"wrong type", pet, map[string]interface{}{"name": 1}, openapi.ErrValueInvalid{Pointer: "/name", Reason: "must be string"}},
+		{"too short", pet, map[string]interface{}{"name": ""}, openapi.ErrValueInvalid{Pointer: "/name", Reason: "length must be >= 1"}},
+		{"not integer", pet, map[string]interface{}{"name": "a", "age": 1.5}, openapi.ErrValueInvalid{Pointer: "/age", Reason: "must be integer"}},
+		{"too large", pet, map[string]interface{}{"name": "a", "age": 31}, openapi.ErrValueInvalid{Pointer: "/age", Reason: "must be <= 30"}},
+		{"too many items", pet, map[string]interface{}{"name": "a", "tags": []interface{}{"a", "b", "c"}}, openapi.ErrValueInvalid{Pointer: "/tags", Reason: "must have <= 2 items"}},
+		{"item type", pet, map[string]interface{}{"name": "a", "tags": []interface{}{"a", 1}}, openapi.ErrValueInvalid{Pointer: "/tags/1", Reason: "must be string"}},
+		{"null", &openapi.Schema{Type: "string"}, nil, openapi.ErrValueInvalid{Reason: "must not be null"}},
+		{"nullable", &openapi.Schema{Type: "string", Nullable: true}, nil, nil},
+		{"enum", &openapi.Schema{Type: "string", Enum: []interface{}{"a", "b"}}, "c", openapi.ErrValueInvalid{Reason: "must be one of: a, b"}},
+		{"integer enum", &openapi.Schema{Type: "integer", Enum: []interface{}{1, 2}}, 2, nil},
+		{"typed enum", &openapi.Schema{Enum: []interface{}{1, true}}, "1", openapi.ErrValueInvalid{Reason: "must be one of: 1, true"}},
+		{"object enum", &openapi.Schema{Type: "object", Enum: []interface{}{map[interface{}]interface{}{"a": 1}}}, map[string]interface{}{"a": 1.0}, nil},
+		{"pattern", &openapi.Schema{Type: "string", Pattern: "^[a-z]+$"}, "A", openapi.ErrValueInvalid{Reason: "must match pattern ^[a-z]+$"}},
+		{"exclusive minimum", &openapi.Schema{Type: "number", Minimum: "1", ExclusiveMinimum: true}, 1, openapi.ErrValueInvalid{Reason: "must be > 1"}},
+		{"multipleOf", &openapi.Schema{Type: "integer", MultipleOf: "5"}, 12, openapi.ErrValueInvalid{Reason: "must be multiple of 5"}},
+		{"decimal multipleOf", &openapi.Schema{Type: "number", MultipleOf: "0.01"}, 0.29, nil},
+		{"not decimal multipleOf", &openapi.Schema{Type: "number", MultipleOf: "0.01"}, 0.295, openapi.ErrValueInvalid{Reason: "must be multiple of 0.01"}},
+		{"zero minimum", &openapi.Schema{Type: "number", Minimum: "0"}, -0.5, openapi.ErrValueInvalid{Reason: "must be >= 0"}},
+		{"decimal exclusive maximum", &openapi.Schema{Type: "number", Maximum: "0.5", ExclusiveMaximum: true}, 0.5, openapi.ErrValueInvalid{Reason: "must be < 0.5"}},
+		{"json.Number", &openapi.Schema{Type: "number", Maximum: "0.1"}, json.Number("0.10000000000000000001"), openapi.ErrValueInvalid{Reason: "must be <= 0.1"}},
+		{"oneOf", &openapi.Schema{OneOf: []*openapi.Schema{{Type: "string"}, {Type: "integer"}}}, true, openapi.ErrValueInvalid{Reason: "must match exactly one of oneOf schemas"}},
+		{"anyOf", &openapi.Schema{AnyOf: []*openapi.Schema{{Type: "string"}, {Type: "integer"}}}, 1, nil},
+		{"not", &openapi.Schema{Not: &openapi.Schema{Type: "string"}}, "a", openapi.ErrValueInvalid{Reason: "must not match the not schema"}},
+		{"additionalProperties", &openapi.Schema{Type: "object", AdditionalProperties: &openapi.Schema{Type: "integer"}}, map[string]interface{}{"a/b": "x"}, openapi.ErrValueInvalid{Pointer: "/a~1b", Reason: "must be integer"}},
+		{"reference cycle", &openapi.Schema{Ref: "#/components/schemas/A"}, 1, openapi.ErrReferenceCycle{Ref: "#/components/schemas/A"}},
+		{"missing root", pet, map[string]interface{}{}, openapi.ErrMissingReferenceRoot},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			root := doc
+			if c.label == "missing root" {
+				root = nil
+			}
+			if err := c.schema.ValidateValue(root, c.value); !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %v, but %v", c.err, err)
+			}
+		})
+	}
+}
+
+func TestSchema_ApplyDefaults(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.2
+info:
+  title: defaults
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        kind:
+          type: string
+          default: cat
+        tags:
+          type: array
+          default: [pet]
+          items:
+            type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      default: {}
+      properties:
+        verified:
+          type: boolean
+          default: false
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet := &openapi.Schema{Ref: "#/components/schemas/Pet"}
+	candidates := []struct {
+		label    string
+		schema   *openapi.Schema
+		value    interface{}
+		expected interface{}
+	}{
+		{"missing", pet, map[string]interface{}{"name": "kitty"}, map[string]interface{}{
+			"name":  "kitty",
+			"kind":  "cat",
+			"tags":  []interface{}{"pet"},
+			"owner": map[string]interface{}{"verified": false},
+		}},
+		{"present", pet, map[string]interface{}{"kind": "dog", "owner": map[string]interface{}{"verified": true}}, map[string]interface{}{
+			"kind":  "dog",
+			"tags":  []interface{}{"pet"},
+			"owner": map[string]interface{}{"verified": true},
+		}},
+		{"null", pet, map[string]interface{}{"kind": nil}, map[string]interface{}{
+			"kind":  nil,
+			"tags":  []interface{}{"pet"},
+			"owner": map[string]interface{}{"verified": false},
+		}},
+		{"items", &openapi.Schema{Type: "array", Items: pet}, []interface{}{map[string]interface{}{"tags": []interface{}{}}}, []interface{}{map[string]interface{}{
+			"kind":  "cat",
+			"tags":  []interface{}{},
+			"owner": map[string]interface{}{"verified": false},
+		}}},
+		{"root", &openapi.Schema{Type: "integer", Default: 10}, nil, 10},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			v, err := c.schema.ApplyDefaults(doc, c.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, c.expected) {
+				t.Errorf("%#v != %#v", v, c.expected)
+			}
+		})
+	}
+	value := map[string]interface{}{}
+	if _, err := pet.ApplyDefaults(doc, value); err != nil {
+		t.Fatal(err)
+	}
+	if len(value) != 0 {
+		t.Errorf("the value should not be modified: %v", value)
+	}
+}
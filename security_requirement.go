@@ -28,6 +28,14 @@ func (secReq *SecurityRequirement) UnmarshalYAML(unmarshal func(interface{}) err
 	return unmarshal(&secReq.mp)
 }
 
+// MarshalYAML implements yaml.Marshaler.
+func (secReq SecurityRequirement) MarshalYAML() (interface{}, error) {
+	if secReq.mp == nil {
+		return map[string][]string{}, nil
+	}
+	return secReq.mp, nil
+}
+
 // Get returns required security schemes. If there is not given name,
 // this function returns nil.
 func (secReq SecurityRequirement) Get(name string) []string {
@@ -4,16 +4,16 @@ package openapi
 
 // SecurityScheme Object
 type SecurityScheme struct {
-	Type             SecuritySchemeType
-	Description      string
-	Name             string
-	In               InType
-	Scheme           string
-	BearerFormat     string `yaml:"bearerFormat"`
-	Flows            *OAuthFlows
-	OpenIDConnectURL string `yaml:"openIdConnectUrl"`
+	Type             SecuritySchemeType `yaml:"type,omitempty"`
+	Description      string             `yaml:"description,omitempty"`
+	Name             string             `yaml:"name,omitempty"`
+	In               InType             `yaml:"in,omitempty"`
+	Scheme           string             `yaml:"scheme,omitempty"`
+	BearerFormat     string             `yaml:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows        `yaml:"flows,omitempty"`
+	OpenIDConnectURL string             `yaml:"openIdConnectUrl,omitempty"`
 
-	Ref string `yaml:"$ref"`
+	Ref string `yaml:"$ref,omitempty"`
 }
 
 // SecuritySchemeType represents a securityScheme.type value.
@@ -3,6 +3,8 @@ package openapi
 import (
 	"net/url"
 	"regexp"
+	"sort"
+	"strings"
 )
 
 // codebeat:disable[TOO_MANY_IVARS]
@@ -11,9 +13,9 @@ var tmplVarRegexp = regexp.MustCompile("{[^}]+}")
 
 // Server Object
 type Server struct {
-	URL         string
-	Description string
-	Variables   map[string]*ServerVariable
+	URL         string                     `yaml:"url,omitempty"`
+	Description string                     `yaml:"description,omitempty"`
+	Variables   map[string]*ServerVariable `yaml:"variables,omitempty"`
 }
 
 // Validate the values of Server object.
@@ -41,3 +43,195 @@ func (server Server) validateRequiredFields() error {
 	}
 	return nil
 }
+
+// Expand returns the URL which the template variables in server.url
+// are substituted with given values. If a value for a variable is not
+// given, the default value of the server variable is used.
+// If the server variable has enum values, the value must be one of them.
+func (server Server) Expand(values map[string]string) (string, error) {
+	var err error
+	expanded := tmplVarRegexp.ReplaceAllStringFunc(server.URL, func(s string) string {
+		name := s[1 : len(s)-1]
+		v, e := server.variableValue(name, values)
+		if e != nil && err == nil {
+			err = e
+		}
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+func (server Server) variableValue(name string, values map[string]string) (string, error) {
+	sv := server.Variables[name]
+	v, ok := values[name]
+	if !ok {
+		if sv == nil {
+			return "", ErrRequired{Target: "value of server variable " + name}
+		}
+		v = sv.Default
+	}
+	if sv != nil && len(sv.Enum) > 0 && !containsString(sv.Enum, v) {
+		return "", ErrMustOneOf{Object: "server variable " + name, ValidValues: sv.Enum}
+	}
+	return v, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether given request URL is under the server URL.
+// If matched, this function returns the values of the server variables
+// and the rest of the path, which is relative to the server URL.
+// If requestURL has no host (e.g. http.Request.URL on server side),
+// only the path part of absolute server.url is compared.
+// The variables in the host match a label, while the variables in the path
+// may match several segments like "v1/api", preferring their defaults.
+// The values of the variables are unescaped.
+func (server Server) Match(requestURL *url.URL) (map[string]string, string, bool) {
+	tmpl := server.URL
+	target := requestURL.EscapedPath()
+	pathPart := tmpl
+	if i := strings.Index(tmpl, "://"); i != -1 {
+		if j := strings.Index(tmpl[i+3:], "/"); j != -1 {
+			pathPart = tmpl[i+3+j:]
+		} else {
+			pathPart = ""
+		}
+		if requestURL.Host == "" {
+			tmpl = pathPart
+		} else {
+			target = requestURL.Scheme + "://" + requestURL.Host + target
+		}
+	}
+	tmpl = strings.TrimSuffix(tmpl, "/")
+	pathVars := map[string]bool{}
+	for _, loc := range tmplVarRegexp.FindAllStringIndex(pathPart, -1) {
+		pathVars[pathPart[loc[0]+1:loc[1]-1]] = true
+	}
+
+	re, names := compileTemplate(tmpl, func(name string) string {
+		sv := server.Variables[name]
+		if sv != nil && len(sv.Enum) > 0 {
+			quoted := make([]string, len(sv.Enum))
+			for i, e := range sv.Enum {
+				quoted[i] = regexp.QuoteMeta(e)
+			}
+			return "(" + strings.Join(quoted, "|") + ")"
+		}
+		if !pathVars[name] {
+			return "([^/]*)"
+		}
+		if sv != nil && sv.Default != "" {
+			return "(" + regexp.QuoteMeta(sv.Default) + "|.*?)"
+		}
+		return "(.*?)"
+	}, "(/.*)?")
+	m := re.FindStringSubmatch(target)
+	if m == nil {
+		return nil, "", false
+	}
+	vars := make(map[string]string, len(names))
+	for i, name := range names {
+		v, err := url.PathUnescape(m[i+1])
+		if err != nil {
+			return nil, "", false
+		}
+		vars[name] = v
+	}
+	rest := m[len(m)-1]
+	if rest == "" {
+		rest = "/"
+	}
+	return vars, rest, true
+}
+
+// compileTemplate compiles a template string containing {name}
+// variables into an anchored regular expression. varPattern returns
+// the capturing group pattern for each variable, and suffix is appended
+// to the end of the expression.
+// Returned names are the variable names in the order of the groups.
+func compileTemplate(tmpl string, varPattern func(name string) string, suffix string) (*regexp.Regexp, []string) {
+	var names []string
+	var buf strings.Builder
+	buf.WriteString("^")
+	last := 0
+	for _, loc := range tmplVarRegexp.FindAllStringIndex(tmpl, -1) {
+		buf.WriteString(regexp.QuoteMeta(tmpl[last:loc[0]]))
+		name := tmpl[loc[0]+1 : loc[1]-1]
+		names = append(names, name)
+		buf.WriteString(varPattern(name))
+		last = loc[1]
+	}
+	buf.WriteString(regexp.QuoteMeta(tmpl[last:]))
+	buf.WriteString(suffix)
+	buf.WriteString("$")
+	return regexp.MustCompile(buf.String()), names
+}
+
+// MatchServer returns the server which matches given request URL.
+// Servers are looked up from the operation, the path item and the document
+// in this order, same as the servers in the operation override the others.
+// The path under the server URL is matched with the paths in the document,
+// and the operation is selected by given method.
+// If no operation matches, document level servers are matched with the URL.
+// This function returns the matched server and the values of its variables.
+func (doc *Document) MatchServer(method string, requestURL *url.URL) (*Server, map[string]string, bool) {
+	if _, server, vars, ok := doc.matchPath(method, requestURL); ok {
+		return server, vars, true
+	}
+	for _, server := range doc.Servers {
+		if vars, _, ok := server.Match(requestURL); ok {
+			return server, vars, true
+		}
+	}
+	return nil, nil, false
+}
+
+// matchPath returns the path in the document which matches given request
+// URL under its servers, with the matched server and the values of its
+// variables.
+func (doc *Document) matchPath(method string, requestURL *url.URL) (string, *Server, map[string]string, bool) {
+	for _, path := range doc.Paths.matchingOrder() {
+		pathItem := doc.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		servers := effectiveServers(doc, pathItem, pathItem.GetOperationByMethod(method))
+		for _, server := range servers {
+			vars, rest, ok := server.Match(requestURL)
+			if !ok {
+				continue
+			}
+			if _, ok := matchPathTemplate(path, rest); ok {
+				return path, server, vars, true
+			}
+		}
+	}
+	return "", nil, nil, false
+}
+
+// matchingOrder returns the paths in order to match with request paths,
+// which prefers the path having fewer templated parts.
+func (paths Paths) matchingOrder() []string {
+	var list []string
+	for path := range paths {
+		list = append(list, path)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		ci, cj := strings.Count(list[i], "{"), strings.Count(list[j], "{")
+		if ci != cj {
+			return ci < cj
+		}
+		return list[i] < list[j]
+	})
+	return list
+}
@@ -1,10 +1,12 @@
 package openapi_test
 
 import (
+	"net/url"
 	"reflect"
+	"strconv"
 	"testing"
 
-	openapi "github.com/nasa9084/go-openapi"
+	openapi "github.com/naoyamaguchi/go-openapi"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -34,3 +36,140 @@ description: Development server`
 		return
 	}
 }
+
+func TestServer_Expand(t *testing.T) {
+	server := openapi.Server{
+		URL: "https://{username}.example.com:{port}/{basePath}",
+		Variables: map[string]*openapi.ServerVariable{
+			"username": &openapi.ServerVariable{Default: "demo"},
+			"port":     &openapi.ServerVariable{Default: "8443", Enum: []string{"8443", "443"}},
+			"basePath": &openapi.ServerVariable{Default: "v2"},
+		},
+	}
+	candidates := []struct {
+		label  string
+		server openapi.Server
+		values map[string]string
+		expect string
+		err    error
+	}{
+		{"defaults", server, nil, "https://demo.example.com:8443/v2", nil},
+		{"with values", server, map[string]string{"username": "foo", "port": "443"}, "https://foo.example.com:443/v2", nil},
+		{"not in enum", server, map[string]string{"port": "80"}, "", openapi.ErrMustOneOf{Object: "server variable port", ValidValues: []string{"8443", "443"}}},
+		{"not declared", openapi.Server{URL: "https://{host}/"}, nil, "", openapi.ErrRequired{Target: "value of server variable host"}},
+		{"no variables", openapi.Server{URL: "/v1"}, nil, "/v1", nil},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			got, err := c.server.Expand(c.values)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %s, but %s", c.err, err)
+				return
+			}
+			if got != c.expect {
+				t.Errorf("%s != %s", got, c.expect)
+			}
+		})
+	}
+}
+
+func TestServer_Match(t *testing.T) {
+	server := openapi.Server{
+		URL: "{scheme}://developer.uspto.gov/{version}/",
+		Variables: map[string]*openapi.ServerVariable{
+			"scheme":  &openapi.ServerVariable{Default: "https", Enum: []string{"https", "http"}},
+			"version": &openapi.ServerVariable{Default: "v1"},
+		},
+	}
+	candidates := []struct {
+		label  string
+		server openapi.Server
+		url    string
+		vars   map[string]string
+		rest   string
+		ok     bool
+	}{
+		{"absolute", server, "https://developer.uspto.gov/v1/fields", map[string]string{"scheme": "https", "version": "v1"}, "/fields", true},
+		{"root", server, "http://developer.uspto.gov/v2", map[string]string{"scheme": "http", "version": "v2"}, "/", true},
+		{"not in enum", server, "ftp://developer.uspto.gov/v1/fields", nil, "", false},
+		{"other host", server, "https://example.com/v1/fields", nil, "", false},
+		{"path only", server, "/v3/fields", map[string]string{"version": "v3"}, "/fields", true},
+		{"relative server", openapi.Server{URL: "/"}, "/pets", map[string]string{}, "/pets", true},
+		{"prefix is not segment", openapi.Server{URL: "/api"}, "/apis", nil, "", false},
+		{"nil variable", openapi.Server{URL: "/{version}", Variables: map[string]*openapi.ServerVariable{"version": nil}}, "/v1/pets", map[string]string{"version": "v1"}, "/pets", true},
+		{"base path", openapi.Server{URL: "https://example.com/{basePath}", Variables: map[string]*openapi.ServerVariable{"basePath": &openapi.ServerVariable{Default: "v1/api"}}}, "https://example.com/v1/api/pets", map[string]string{"basePath": "v1/api"}, "/pets", true},
+		{"host variable", openapi.Server{URL: "https://{region}.example.com/api", Variables: map[string]*openapi.ServerVariable{"region": &openapi.ServerVariable{Default: "eu"}}}, "https://us.example.com/api/pets", map[string]string{"region": "us"}, "/pets", true},
+		{"escaped", openapi.Server{URL: "/{tenant}"}, "/acme%20corp/pets", map[string]string{"tenant": "acme corp"}, "/pets", true},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			u, err := url.Parse(c.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars, rest, ok := c.server.Match(u)
+			if ok != c.ok {
+				t.Errorf("%t != %t", ok, c.ok)
+				return
+			}
+			if !reflect.DeepEqual(vars, c.vars) {
+				t.Errorf("%+v != %+v", vars, c.vars)
+			}
+			if rest != c.rest {
+				t.Errorf("%s != %s", rest, c.rest)
+			}
+		})
+	}
+}
+
+func TestDocument_MatchServer(t *testing.T) {
+	docServer := &openapi.Server{URL: "https://api.example.com/v1"}
+	pathServer := &openapi.Server{URL: "https://files.example.com/v1"}
+	opServer := &openapi.Server{
+		URL:       "https://{region}.upload.example.com/v1",
+		Variables: map[string]*openapi.ServerVariable{"region": &openapi.ServerVariable{Default: "us"}},
+	}
+	doc := &openapi.Document{
+		Servers: []*openapi.Server{docServer},
+		Paths: openapi.Paths{
+			"/pets/{petId}": &openapi.PathItem{Get: &openapi.Operation{}},
+			"/files/{name}": &openapi.PathItem{
+				Servers: []*openapi.Server{pathServer},
+				Get:     &openapi.Operation{},
+				Put:     &openapi.Operation{Servers: []*openapi.Server{opServer}},
+			},
+		},
+	}
+	candidates := []struct {
+		method string
+		url    string
+		server *openapi.Server
+		vars   map[string]string
+	}{
+		{"GET", "https://api.example.com/v1/pets/1", docServer, map[string]string{}},
+		{"GET", "https://files.example.com/v1/files/a.txt", pathServer, map[string]string{}},
+		{"PUT", "https://eu.upload.example.com/v1/files/a.txt", opServer, map[string]string{"region": "eu"}},
+		{"PUT", "https://files.example.com/v1/files/a.txt", nil, nil},
+		{"GET", "https://api.example.com/v1/unknown", docServer, map[string]string{}},
+		{"GET", "https://example.com/", nil, nil},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.method+" "+c.url, func(t *testing.T) {
+			u, err := url.Parse(c.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, vars, ok := doc.MatchServer(c.method, u)
+			if ok != (c.server != nil) {
+				t.Errorf("unexpected match result: %t", ok)
+				return
+			}
+			if server != c.server {
+				t.Errorf("%+v != %+v", server, c.server)
+			}
+			if !reflect.DeepEqual(vars, c.vars) {
+				t.Errorf("%+v != %+v", vars, c.vars)
+			}
+		})
+	}
+}
@@ -4,9 +4,9 @@ package openapi
 
 // ServerVariable Object
 type ServerVariable struct {
-	Enum        []string
-	Default     string
-	Description string
+	Enum        []string `yaml:"enum,omitempty"`
+	Default     string   `yaml:"default,omitempty"`
+	Description string   `yaml:"description,omitempty"`
 }
 
 // Validate the values of Server Variable object.
@@ -0,0 +1,659 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Style values for parameter.style and encoding.style.
+const (
+	StyleMatrix         = "matrix"
+	StyleLabel          = "label"
+	StyleForm           = "form"
+	StyleSimple         = "simple"
+	StyleSpaceDelimited = "spaceDelimited"
+	StylePipeDelimited  = "pipeDelimited"
+	StyleDeepObject     = "deepObject"
+)
+
+// StyleList is a list of valid values of parameter.style.
+var StyleList = []string{StyleMatrix, StyleLabel, StyleForm, StyleSimple, StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject}
+
+// reservedReplacer restores the characters in RFC3986 reserved set
+// from percent-encoded form. It is used when allowReserved is true.
+var reservedReplacer = strings.NewReplacer(
+	"%3A", ":", "%2F", "/", "%3F", "?", "%23", "#", "%5B", "[", "%5D", "]", "%40", "@",
+	"%21", "!", "%24", "$", "%26", "&", "%27", "'", "%28", "(", "%29", ")",
+	"%2A", "*", "%2B", "+", "%2C", ",", "%3B", ";", "%3D", "=",
+)
+
+// styleCodec serializes and deserializes a value according to the
+// style, explode and allowReserved fields.
+type styleCodec struct {
+	name          string
+	in            InType
+	style         string
+	explode       bool
+	allowReserved bool
+	schema        *Schema
+}
+
+func defaultStyle(in InType) string {
+	switch in {
+	case InQuery, InCookie:
+		return StyleForm
+	default:
+		return StyleSimple
+	}
+}
+
+// GetStyle returns the style of the parameter.
+// If parameter.style is not set, this function returns the default
+// value for parameter.in: form for query and cookie, simple for path and header.
+func (parameter Parameter) GetStyle() string {
+	if parameter.Style != "" {
+		return parameter.Style
+	}
+	return defaultStyle(parameter.In)
+}
+
+// GetExplode returns the explode value of the parameter.
+// If parameter.explode is not set, this function returns true when
+// the style is form, false otherwise.
+func (parameter Parameter) GetExplode() bool {
+	if parameter.Explode != nil {
+		return *parameter.Explode
+	}
+	return parameter.GetStyle() == StyleForm
+}
+
+func (parameter Parameter) codec() styleCodec {
+	return styleCodec{
+		name:          parameter.Name,
+		in:            parameter.In,
+		style:         parameter.GetStyle(),
+		explode:       parameter.GetExplode(),
+		allowReserved: parameter.AllowReserved,
+		schema:        parameter.Schema,
+	}
+}
+
+// Serialize given value according to the style of the parameter.
+// The value should be a primitive, a slice or a map with string keys.
+// The returned string is the form which appears in the request:
+// a path segment for path parameters, a query string for query parameters,
+// a header value for header parameters and a cookie pair for cookie parameters.
+func (parameter Parameter) Serialize(value interface{}) (string, error) {
+	return parameter.codec().serialize(value)
+}
+
+// Deserialize given string which is serialized according to the style
+// of the parameter. The types of returned value are coerced by the
+// parameter.schema: int64 for integer, float64 for number, bool for boolean,
+// []interface{} for array and map[string]interface{} for object.
+// If the parameter does not appear in the string, this function
+// returns nil without error.
+func (parameter Parameter) Deserialize(s string) (interface{}, error) {
+	return parameter.codec().deserialize(s)
+}
+
+// GetStyle returns the style of the header.
+// If header.style is not set, this function returns simple, which is
+// the only style allowed for headers.
+func (header Header) GetStyle() string {
+	if header.Style != "" {
+		return header.Style
+	}
+	return StyleSimple
+}
+
+// GetExplode returns the explode value of the header.
+// If header.explode is not set, this function returns false.
+func (header Header) GetExplode() bool {
+	if header.Explode != nil {
+		return *header.Explode
+	}
+	return false
+}
+
+func (header Header) codec() styleCodec {
+	return styleCodec{
+		name:    "header",
+		in:      InHeader,
+		style:   header.GetStyle(),
+		explode: header.GetExplode(),
+		schema:  header.Schema,
+	}
+}
+
+// Serialize given value into the header value according to the style of
+// the header. See Parameter.Serialize for details.
+func (header Header) Serialize(value interface{}) (string, error) {
+	return header.codec().serialize(value)
+}
+
+// Deserialize given header value according to the style of the header.
+// See Parameter.Deserialize for details.
+func (header Header) Deserialize(s string) (interface{}, error) {
+	return header.codec().deserialize(s)
+}
+
+// GetStyle returns the style of the encoding.
+// If encoding.style is not set, this function returns form.
+func (encoding Encoding) GetStyle() string {
+	if encoding.Style != "" {
+		return encoding.Style
+	}
+	return StyleForm
+}
+
+// GetExplode returns the explode value of the encoding.
+// If encoding.explode is not set, this function returns true when
+// the style is form, false otherwise.
+func (encoding Encoding) GetExplode() bool {
+	if encoding.Explode != nil {
+		return *encoding.Explode
+	}
+	return encoding.GetStyle() == StyleForm
+}
+
+func (encoding Encoding) codec(name string, schema *Schema) styleCodec {
+	return styleCodec{
+		name:          name,
+		in:            InQuery,
+		style:         encoding.GetStyle(),
+		explode:       encoding.GetExplode(),
+		allowReserved: encoding.AllowReserved,
+		schema:        schema,
+	}
+}
+
+// Serialize given value of the property in
+// application/x-www-form-urlencoded body according to the style of the
+// encoding. The schema is the schema of the property.
+// The returned string is the pairs of the name and the value, like
+// "name=value". See Parameter.Serialize for details.
+func (encoding Encoding) Serialize(name string, schema *Schema, value interface{}) (string, error) {
+	return encoding.codec(name, schema).serialize(value)
+}
+
+// Deserialize the value of the property from given
+// application/x-www-form-urlencoded body according to the style of the
+// encoding. See Parameter.Deserialize for details.
+func (encoding Encoding) Deserialize(name string, schema *Schema, s string) (interface{}, error) {
+	return encoding.codec(name, schema).deserialize(s)
+}
+
+func (c styleCodec) escape(s string) string {
+	if c.in == InHeader {
+		return s
+	}
+	escaped := strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+	if c.allowReserved {
+		return reservedReplacer.Replace(escaped)
+	}
+	return escaped
+}
+
+func (c styleCodec) unescape(s string) (string, error) {
+	if c.in == InHeader {
+		return s, nil
+	}
+	unescaped, err := url.PathUnescape(s)
+	if err != nil {
+		return "", c.errFormat()
+	}
+	return unescaped, nil
+}
+
+func (c styleCodec) errFormat() error {
+	return ErrFormatInvalid{Target: c.name, Format: c.style}
+}
+
+type styleValueKind int
+
+const (
+	primitiveValue styleValueKind = iota
+	arrayValue
+	objectValue
+)
+
+// flatten converts given value into string representations.
+// For arrays, returned values are the items. For objects,
+// returned keys and values are sorted by key.
+func (c styleCodec) flatten(value interface{}) (styleValueKind, []string, []string, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return primitiveValue, nil, nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return primitiveValue, nil, nil, nil
+	case reflect.Slice, reflect.Array:
+		values := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values[i] = c.escape(fmt.Sprint(rv.Index(i).Interface()))
+		}
+		return arrayValue, nil, values, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return primitiveValue, nil, nil, ErrStyleNotApplicable
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		values := make([]string, len(keys))
+		for i, k := range keys {
+			values[i] = c.escape(fmt.Sprint(rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface()))
+			keys[i] = c.escape(k)
+		}
+		return objectValue, keys, values, nil
+	case reflect.Struct, reflect.Func, reflect.Chan:
+		return primitiveValue, nil, nil, ErrStyleNotApplicable
+	}
+	return primitiveValue, nil, []string{c.escape(fmt.Sprint(rv.Interface()))}, nil
+}
+
+func (c styleCodec) serialize(value interface{}) (string, error) {
+	kind, keys, values, err := c.flatten(value)
+	if err != nil {
+		return "", err
+	}
+	name := c.escape(c.name)
+	switch c.style {
+	case StyleMatrix:
+		return c.serializePrefixed(";", ";"+name, kind, keys, values), nil
+	case StyleLabel:
+		return c.serializePrefixed(".", ".", kind, keys, values), nil
+	case StyleSimple:
+		if kind == objectValue && c.explode {
+			return strings.Join(joinPairs(keys, values, "="), ","), nil
+		}
+		return strings.Join(interleave(kind, keys, values), ","), nil
+	case StyleForm:
+		return c.serializeDelimited(name, ",", kind, keys, values), nil
+	case StyleSpaceDelimited, StylePipeDelimited:
+		if kind == primitiveValue {
+			return "", ErrStyleNotApplicable
+		}
+		delim := "%20"
+		if c.style == StylePipeDelimited {
+			delim = "|"
+		}
+		return c.serializeDelimited(name, delim, kind, keys, values), nil
+	case StyleDeepObject:
+		if kind != objectValue || !c.explode {
+			return "", ErrStyleNotApplicable
+		}
+		pairs := make([]string, len(keys))
+		for i := range keys {
+			pairs[i] = name + "%5B" + keys[i] + "%5D=" + values[i]
+		}
+		return strings.Join(pairs, "&"), nil
+	}
+	return "", ErrMustOneOf{Object: "style", ValidValues: StyleList}
+}
+
+// serializePrefixed serializes values in matrix or label style.
+// sep is the separator used when exploded, and head is the prefix.
+func (c styleCodec) serializePrefixed(sep, head string, kind styleValueKind, keys, values []string) string {
+	assign := ""
+	if sep == ";" {
+		assign = "="
+	}
+	if len(values) == 0 {
+		return head
+	}
+	if !c.explode {
+		return head + assign + strings.Join(interleave(kind, keys, values), ",")
+	}
+	switch kind {
+	case arrayValue:
+		if sep == ";" {
+			return head + "=" + strings.Join(values, head+"=")
+		}
+		return sep + strings.Join(values, sep)
+	case objectValue:
+		return sep + strings.Join(joinPairs(keys, values, "="), sep)
+	}
+	return head + assign + values[0]
+}
+
+// serializeDelimited serializes values in form, spaceDelimited or pipeDelimited style.
+func (c styleCodec) serializeDelimited(name, delim string, kind styleValueKind, keys, values []string) string {
+	if c.explode {
+		switch kind {
+		case arrayValue:
+			pairs := make([]string, len(values))
+			for i, v := range values {
+				pairs[i] = name + "=" + v
+			}
+			return strings.Join(pairs, "&")
+		case objectValue:
+			return strings.Join(joinPairs(keys, values, "="), "&")
+		}
+	}
+	return name + "=" + strings.Join(interleave(kind, keys, values), delim)
+}
+
+func interleave(kind styleValueKind, keys, values []string) []string {
+	if kind != objectValue {
+		return values
+	}
+	ret := make([]string, 0, len(keys)*2)
+	for i := range keys {
+		ret = append(ret, keys[i], values[i])
+	}
+	return ret
+}
+
+func joinPairs(keys, values []string, sep string) []string {
+	ret := make([]string, len(keys))
+	for i := range keys {
+		ret[i] = keys[i] + sep + values[i]
+	}
+	return ret
+}
+
+func (c styleCodec) kind() styleValueKind {
+	if c.schema == nil {
+		return primitiveValue
+	}
+	switch c.schema.Type {
+	case "array":
+		return arrayValue
+	case "object":
+		return objectValue
+	}
+	return primitiveValue
+}
+
+func (c styleCodec) deserialize(s string) (interface{}, error) {
+	switch c.style {
+	case StyleMatrix:
+		return c.deserializeMatrix(s)
+	case StyleLabel:
+		if !strings.HasPrefix(s, ".") {
+			return nil, c.errFormat()
+		}
+		sep := ","
+		if c.explode {
+			sep = "."
+		}
+		return c.deserializeList(s[1:], sep, c.explode)
+	case StyleSimple:
+		return c.deserializeList(s, ",", c.explode)
+	case StyleForm, StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject:
+		return c.deserializeQuery(s)
+	}
+	return nil, ErrMustOneOf{Object: "style", ValidValues: StyleList}
+}
+
+// deserializeList deserializes a list separated with sep.
+// If pairs is true, the items of the object are formed as key=value.
+func (c styleCodec) deserializeList(s, sep string, pairs bool) (interface{}, error) {
+	switch c.kind() {
+	case arrayValue:
+		if s == "" {
+			return []interface{}{}, nil
+		}
+		return c.coerceArray(strings.Split(s, sep))
+	case objectValue:
+		if s == "" {
+			return map[string]interface{}{}, nil
+		}
+		items := strings.Split(s, sep)
+		if pairs {
+			return c.coerceObjectPairs(items, "=")
+		}
+		return c.coerceObjectList(items)
+	}
+	return c.coerce(s, c.schema)
+}
+
+func (c styleCodec) deserializeMatrix(s string) (interface{}, error) {
+	if !strings.HasPrefix(s, ";") {
+		return nil, c.errFormat()
+	}
+	items := strings.Split(s[1:], ";")
+	if c.explode {
+		switch c.kind() {
+		case arrayValue:
+			values := make([]string, 0, len(items))
+			for _, item := range items {
+				kv := strings.SplitN(item, "=", 2)
+				if len(kv) != 2 || kv[0] != c.escape(c.name) {
+					return nil, c.errFormat()
+				}
+				values = append(values, kv[1])
+			}
+			return c.coerceArray(values)
+		case objectValue:
+			return c.coerceObjectPairs(items, "=")
+		}
+	}
+	kv := strings.SplitN(items[0], "=", 2)
+	if len(items) != 1 || kv[0] != c.escape(c.name) {
+		return nil, c.errFormat()
+	}
+	if len(kv) == 1 {
+		kv = append(kv, "")
+	}
+	return c.deserializeList(kv[1], ",", false)
+}
+
+func (c styleCodec) deserializeQuery(s string) (interface{}, error) {
+	type pair struct{ key, value string }
+	var pairs []pair
+	for _, item := range strings.Split(s, "&") {
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 1 {
+			kv = append(kv, "")
+		}
+		key, err := c.unescape(kv[0])
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair{key: key, value: kv[1]})
+	}
+	var values []string
+	for _, p := range pairs {
+		if p.key == c.name {
+			values = append(values, p.value)
+		}
+	}
+
+	delim := ","
+	switch c.style {
+	case StyleSpaceDelimited:
+		delim = "%20"
+	case StylePipeDelimited:
+		delim = "|"
+	case StyleDeepObject:
+		if c.kind() != objectValue || !c.explode {
+			return nil, ErrStyleNotApplicable
+		}
+		obj := map[string]interface{}{}
+		found := false
+		for _, p := range pairs {
+			if !strings.HasPrefix(p.key, c.name+"[") || !strings.HasSuffix(p.key, "]") {
+				continue
+			}
+			found = true
+			key := strings.TrimSuffix(strings.TrimPrefix(p.key, c.name+"["), "]")
+			v, err := c.coerce(p.value, c.propertySchema(key))
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+		if !found {
+			return nil, nil
+		}
+		return obj, nil
+	}
+	if c.style != StyleForm && c.kind() == primitiveValue {
+		return nil, ErrStyleNotApplicable
+	}
+
+	if c.explode {
+		switch c.kind() {
+		case arrayValue:
+			if values == nil {
+				return nil, nil
+			}
+			return c.coerceArray(values)
+		case objectValue:
+			obj := map[string]interface{}{}
+			for _, p := range pairs {
+				if !c.hasProperty(p.key) {
+					continue
+				}
+				v, err := c.coerce(p.value, c.propertySchema(p.key))
+				if err != nil {
+					return nil, err
+				}
+				obj[p.key] = v
+			}
+			if len(obj) == 0 {
+				return nil, nil
+			}
+			return obj, nil
+		}
+	}
+	if values == nil {
+		return nil, nil
+	}
+	if len(values) != 1 {
+		return nil, c.errFormat()
+	}
+	return c.deserializeList(values[0], delim, false)
+}
+
+func (c styleCodec) hasProperty(key string) bool {
+	if c.schema == nil || c.schema.Properties == nil {
+		return key != c.name
+	}
+	if _, ok := c.schema.Properties[key]; ok {
+		return true
+	}
+	return c.schema.AdditionalProperties != nil
+}
+
+func (c styleCodec) propertySchema(key string) *Schema {
+	if c.schema == nil {
+		return nil
+	}
+	if s, ok := c.schema.Properties[key]; ok {
+		return s
+	}
+	return c.schema.AdditionalProperties
+}
+
+func (c styleCodec) coerceArray(items []string) ([]interface{}, error) {
+	var itemSchema *Schema
+	if c.schema != nil {
+		itemSchema = c.schema.Items
+	}
+	ret := make([]interface{}, len(items))
+	for i, item := range items {
+		v, err := c.coerce(item, itemSchema)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+// coerceObjectList coerces a list formed as key1,value1,key2,value2.
+func (c styleCodec) coerceObjectList(items []string) (map[string]interface{}, error) {
+	if len(items)%2 != 0 {
+		return nil, c.errFormat()
+	}
+	ret := map[string]interface{}{}
+	for i := 0; i < len(items); i += 2 {
+		key, err := c.unescape(items[i])
+		if err != nil {
+			return nil, err
+		}
+		v, err := c.coerce(items[i+1], c.propertySchema(key))
+		if err != nil {
+			return nil, err
+		}
+		ret[key] = v
+	}
+	return ret, nil
+}
+
+// coerceObjectPairs coerces a list formed as key1=value1,key2=value2.
+func (c styleCodec) coerceObjectPairs(items []string, sep string) (map[string]interface{}, error) {
+	ret := map[string]interface{}{}
+	for _, item := range items {
+		kv := strings.SplitN(item, sep, 2)
+		if len(kv) != 2 {
+			return nil, c.errFormat()
+		}
+		key, err := c.unescape(kv[0])
+		if err != nil {
+			return nil, err
+		}
+		v, err := c.coerce(kv[1], c.propertySchema(key))
+		if err != nil {
+			return nil, err
+		}
+		ret[key] = v
+	}
+	return ret, nil
+}
+
+// coerce unescapes given string and converts it into the type
+// specified by the schema.
+func (c styleCodec) coerce(s string, schema *Schema) (interface{}, error) {
+	s, err := c.unescape(s)
+	if err != nil {
+		return nil, err
+	}
+	return coercePrimitive(s, schema, c.name)
+}
+
+// coercePrimitive converts given string into the type specified by
+// the schema. name is used for the error.
+func coercePrimitive(s string, schema *Schema, name string) (interface{}, error) {
+	if schema == nil {
+		return s, nil
+	}
+	switch schema.Type {
+	case "integer":
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ErrFormatInvalid{Target: name, Format: schema.Type}
+		}
+		return i, nil
+	case "number":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, ErrFormatInvalid{Target: name, Format: schema.Type}
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, ErrFormatInvalid{Target: name, Format: schema.Type}
+		}
+		return b, nil
+	}
+	return s, nil
+}
@@ -0,0 +1,232 @@
+package openapi_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestParameter_GetStyle(t *testing.T) {
+	candidates := []struct {
+		in      openapi.Parameter
+		style   string
+		explode bool
+	}{
+		{openapi.Parameter{In: "query"}, "form", true},
+		{openapi.Parameter{In: "cookie"}, "form", true},
+		{openapi.Parameter{In: "path"}, "simple", false},
+		{openapi.Parameter{In: "header"}, "simple", false},
+		{openapi.Parameter{In: "query", Explode: boolPtr(false)}, "form", false},
+		{openapi.Parameter{In: "path", Style: "label", Explode: boolPtr(true)}, "label", true},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if style := c.in.GetStyle(); style != c.style {
+				t.Errorf("%s != %s", style, c.style)
+			}
+			if explode := c.in.GetExplode(); explode != c.explode {
+				t.Errorf("%t != %t", explode, c.explode)
+			}
+		})
+	}
+}
+
+var (
+	styleArraySchema  = &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}
+	styleObjectSchema = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"R": &openapi.Schema{Type: "integer"},
+			"G": &openapi.Schema{Type: "integer"},
+			"B": &openapi.Schema{Type: "integer"},
+		},
+	}
+	styleArray  = []interface{}{"blue", "black", "brown"}
+	styleObject = map[string]interface{}{"R": int64(100), "G": int64(200), "B": int64(150)}
+)
+
+func TestParameter_Serialize(t *testing.T) {
+	candidates := []struct {
+		label   string
+		style   string
+		in      openapi.InType
+		explode bool
+		schema  *openapi.Schema
+		value   interface{}
+		expect  string
+	}{
+		{"matrix/primitive", "matrix", "path", false, nil, "blue", ";color=blue"},
+		{"matrix/array", "matrix", "path", false, styleArraySchema, styleArray, ";color=blue,black,brown"},
+		{"matrix/array/explode", "matrix", "path", true, styleArraySchema, styleArray, ";color=blue;color=black;color=brown"},
+		{"matrix/object", "matrix", "path", false, styleObjectSchema, styleObject, ";color=B,150,G,200,R,100"},
+		{"matrix/object/explode", "matrix", "path", true, styleObjectSchema, styleObject, ";B=150;G=200;R=100"},
+		{"label/primitive", "label", "path", false, nil, "blue", ".blue"},
+		{"label/array", "label", "path", false, styleArraySchema, styleArray, ".blue,black,brown"},
+		{"label/array/explode", "label", "path", true, styleArraySchema, styleArray, ".blue.black.brown"},
+		{"label/object/explode", "label", "path", true, styleObjectSchema, styleObject, ".B=150.G=200.R=100"},
+		{"form/primitive", "form", "query", false, nil, "blue", "color=blue"},
+		{"form/array", "form", "query", false, styleArraySchema, styleArray, "color=blue,black,brown"},
+		{"form/array/explode", "form", "query", true, styleArraySchema, styleArray, "color=blue&color=black&color=brown"},
+		{"form/object", "form", "query", false, styleObjectSchema, styleObject, "color=B,150,G,200,R,100"},
+		{"form/object/explode", "form", "query", true, styleObjectSchema, styleObject, "B=150&G=200&R=100"},
+		{"simple/primitive", "simple", "path", false, nil, "blue", "blue"},
+		{"simple/array", "simple", "header", false, styleArraySchema, styleArray, "blue,black,brown"},
+		{"simple/object", "simple", "path", false, styleObjectSchema, styleObject, "B,150,G,200,R,100"},
+		{"simple/object/explode", "simple", "path", true, styleObjectSchema, styleObject, "B=150,G=200,R=100"},
+		{"spaceDelimited/array", "spaceDelimited", "query", false, styleArraySchema, styleArray, "color=blue%20black%20brown"},
+		{"pipeDelimited/array", "pipeDelimited", "query", false, styleArraySchema, styleArray, "color=blue|black|brown"},
+		{"deepObject/object", "deepObject", "query", true, styleObjectSchema, styleObject, "color%5BB%5D=150&color%5BG%5D=200&color%5BR%5D=100"},
+		{"escaped", "form", "query", false, nil, "a b,c", "color=a%20b%2Cc"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			parameter := openapi.Parameter{Name: "color", In: c.in, Style: c.style, Explode: boolPtr(c.explode), Schema: c.schema}
+			got, err := parameter.Serialize(c.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expect {
+				t.Errorf("%s != %s", got, c.expect)
+			}
+			v, err := parameter.Deserialize(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, c.value) {
+				t.Errorf("%#v != %#v", v, c.value)
+			}
+		})
+	}
+}
+
+func TestHeader_Serialize(t *testing.T) {
+	candidates := []struct {
+		label  string
+		header openapi.Header
+		value  interface{}
+		expect string
+	}{
+		{"primitive", openapi.Header{Schema: &openapi.Schema{Type: "integer"}}, int64(100), "100"},
+		{"array", openapi.Header{Schema: styleArraySchema}, styleArray, "blue,black,brown"},
+		{"object", openapi.Header{Schema: styleObjectSchema}, styleObject, "B,150,G,200,R,100"},
+		{"object/explode", openapi.Header{Explode: boolPtr(true), Schema: styleObjectSchema}, styleObject, "B=150,G=200,R=100"},
+		{"notEscaped", openapi.Header{Schema: &openapi.Schema{Type: "string"}}, "a b", "a b"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			if style := c.header.GetStyle(); style != "simple" {
+				t.Errorf("%s != simple", style)
+			}
+			got, err := c.header.Serialize(c.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expect {
+				t.Errorf("%s != %s", got, c.expect)
+			}
+			v, err := c.header.Deserialize(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, c.value) {
+				t.Errorf("%#v != %#v", v, c.value)
+			}
+		})
+	}
+}
+
+func TestEncoding_Serialize(t *testing.T) {
+	candidates := []struct {
+		label    string
+		encoding openapi.Encoding
+		schema   *openapi.Schema
+		value    interface{}
+		expect   string
+	}{
+		{"default", openapi.Encoding{}, styleArraySchema, styleArray, "color=blue&color=black&color=brown"},
+		{"notExploded", openapi.Encoding{Explode: boolPtr(false)}, styleArraySchema, styleArray, "color=blue,black,brown"},
+		{"pipeDelimited", openapi.Encoding{Style: "pipeDelimited"}, styleArraySchema, styleArray, "color=blue|black|brown"},
+		{"deepObject", openapi.Encoding{Style: "deepObject", Explode: boolPtr(true)}, styleObjectSchema, styleObject, "color%5BB%5D=150&color%5BG%5D=200&color%5BR%5D=100"},
+		{"allowReserved", openapi.Encoding{AllowReserved: true}, &openapi.Schema{Type: "string"}, "a/b", "color=a/b"},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			got, err := c.encoding.Serialize("color", c.schema, c.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expect {
+				t.Errorf("%s != %s", got, c.expect)
+			}
+			v, err := c.encoding.Deserialize("color", c.schema, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, c.value) {
+				t.Errorf("%#v != %#v", v, c.value)
+			}
+		})
+	}
+}
+
+func TestParameter_SerializeError(t *testing.T) {
+	candidates := []struct {
+		label     string
+		parameter openapi.Parameter
+		value     interface{}
+		err       error
+	}{
+		{"unknown style", openapi.Parameter{Name: "id", In: "query", Style: "foo"}, "1", openapi.ErrMustOneOf{Object: "style", ValidValues: openapi.StyleList}},
+		{"spaceDelimited primitive", openapi.Parameter{Name: "id", In: "query", Style: "spaceDelimited"}, "1", openapi.ErrStyleNotApplicable},
+		{"deepObject array", openapi.Parameter{Name: "id", In: "query", Style: "deepObject", Explode: boolPtr(true)}, []string{"1"}, openapi.ErrStyleNotApplicable},
+		{"struct", openapi.Parameter{Name: "id", In: "query"}, struct{}{}, openapi.ErrStyleNotApplicable},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			if _, err := c.parameter.Serialize(c.value); !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %s, but %s", c.err, err)
+			}
+		})
+	}
+}
+
+func TestParameter_Deserialize(t *testing.T) {
+	candidates := []struct {
+		label     string
+		parameter openapi.Parameter
+		in        string
+		expect    interface{}
+		err       error
+	}{
+		{"integer", openapi.Parameter{Name: "id", In: "path", Schema: &openapi.Schema{Type: "integer"}}, "5", int64(5), nil},
+		{"invalid integer", openapi.Parameter{Name: "id", In: "path", Schema: &openapi.Schema{Type: "integer"}}, "five", nil, openapi.ErrFormatInvalid{Target: "id", Format: "integer"}},
+		{"boolean", openapi.Parameter{Name: "flag", In: "query", Schema: &openapi.Schema{Type: "boolean"}}, "flag=true&x=1", true, nil},
+		{"missing query", openapi.Parameter{Name: "id", In: "query"}, "x=1", nil, nil},
+		{"number array", openapi.Parameter{Name: "n", In: "query", Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "number"}}}, "n=1.5&n=2", []interface{}{1.5, 2.0}, nil},
+		{"matrix without prefix", openapi.Parameter{Name: "id", In: "path", Style: "matrix"}, "id=5", nil, openapi.ErrFormatInvalid{Target: "id", Format: "matrix"}},
+		{"matrix empty", openapi.Parameter{Name: "id", In: "path", Style: "matrix"}, ";id", "", nil},
+		{"header not escaped", openapi.Parameter{Name: "X-Id", In: "header"}, "a%20b", "a%20b", nil},
+	}
+	for i, c := range candidates {
+		t.Run(strconv.Itoa(i)+"/"+c.label, func(t *testing.T) {
+			got, err := c.parameter.Deserialize(c.in)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error should be %s, but %s", c.err, err)
+				return
+			}
+			if !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("%#v != %#v", got, c.expect)
+			}
+		})
+	}
+}
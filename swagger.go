@@ -0,0 +1,499 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ConvertSwagger converts a Swagger 2.0 spec into an OpenAPI 3.0 document.
+// The host, the basePath and the schemes are converted into the servers,
+// the definitions into the component schemas, the body and formData
+// parameters into the request bodies, and the securityDefinitions into
+// the security schemes. The consumes and produces are used as the media
+// types of the request bodies and the responses.
+func ConvertSwagger(b []byte) (*Document, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	swagger, ok := yamlToJSONValue(v).(map[string]interface{})
+	if !ok || fmt.Sprint(swagger["swagger"]) != "2.0" && fmt.Sprint(swagger["swagger"]) != "2" {
+		return nil, ErrUnsupportedVersion
+	}
+	c := &swaggerConverter{swagger: swagger}
+	out, err := yaml.Marshal(c.convert())
+	if err != nil {
+		return nil, err
+	}
+	return Load(out)
+}
+
+type swaggerConverter struct {
+	swagger map[string]interface{}
+}
+
+// mediaTypes returns the media types of the object, or the default ones.
+func mediaTypes(obj map[string]interface{}, key string, defaults []string) []string {
+	list, ok := obj[key].([]interface{})
+	if !ok || len(list) == 0 {
+		return defaults
+	}
+	var types []string
+	for _, t := range list {
+		if s, ok := t.(string); ok {
+			types = append(types, s)
+		}
+	}
+	return types
+}
+
+func (c *swaggerConverter) consumes() []string {
+	return mediaTypes(c.swagger, "consumes", []string{"application/json"})
+}
+
+func (c *swaggerConverter) produces() []string {
+	return mediaTypes(c.swagger, "produces", []string{"application/json"})
+}
+
+func (c *swaggerConverter) convert() map[string]interface{} {
+	doc := map[string]interface{}{"openapi": "3.0.2"}
+	for _, key := range []string{"info", "tags", "security", "externalDocs"} {
+		if v, ok := c.swagger[key]; ok {
+			doc[key] = v
+		}
+	}
+	copyExtensions(doc, c.swagger)
+	if servers := c.servers(); len(servers) > 0 {
+		doc["servers"] = servers
+	}
+	components := map[string]interface{}{}
+	if definitions, ok := c.swagger["definitions"].(map[string]interface{}); ok {
+		schemas := map[string]interface{}{}
+		for name, schema := range definitions {
+			schemas[name] = convertSwaggerSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if params, ok := c.swagger["parameters"].(map[string]interface{}); ok {
+		parameters := map[string]interface{}{}
+		requestBodies := map[string]interface{}{}
+		for name, param := range params {
+			p, ok := param.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch p["in"] {
+			case "body":
+				requestBodies[name] = c.requestBody(p, c.consumes())
+			case "formData":
+				// inlined where they are referred
+			default:
+				parameters[name] = convertSwaggerParameter(p)
+			}
+		}
+		if len(parameters) > 0 {
+			components["parameters"] = parameters
+		}
+		if len(requestBodies) > 0 {
+			components["requestBodies"] = requestBodies
+		}
+	}
+	if resps, ok := c.swagger["responses"].(map[string]interface{}); ok {
+		responses := map[string]interface{}{}
+		for name, resp := range resps {
+			responses[name] = c.response(resp, c.produces())
+		}
+		components["responses"] = responses
+	}
+	if defs, ok := c.swagger["securityDefinitions"].(map[string]interface{}); ok {
+		schemes := map[string]interface{}{}
+		for name, def := range defs {
+			if d, ok := def.(map[string]interface{}); ok {
+				schemes[name] = convertSwaggerSecurityScheme(d)
+			}
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+	paths := map[string]interface{}{}
+	if swaggerPaths, ok := c.swagger["paths"].(map[string]interface{}); ok {
+		for path, item := range swaggerPaths {
+			if pathItem, ok := item.(map[string]interface{}); ok {
+				paths[path] = c.pathItem(pathItem)
+			}
+		}
+	}
+	doc["paths"] = paths
+	return doc
+}
+
+func copyExtensions(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if strings.HasPrefix(k, "x-") {
+			dst[k] = v
+		}
+	}
+}
+
+func (c *swaggerConverter) servers() []interface{} {
+	host, _ := c.swagger["host"].(string)
+	basePath, _ := c.swagger["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	schemes := mediaTypes(c.swagger, "schemes", []string{"https"})
+	var servers []interface{}
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func (c *swaggerConverter) pathItem(item map[string]interface{}) map[string]interface{} {
+	pathItem := map[string]interface{}{}
+	copyExtensions(pathItem, item)
+	if ref, ok := item["$ref"]; ok {
+		pathItem["$ref"] = ref
+	}
+	var common []interface{}
+	if params, ok := item["parameters"].([]interface{}); ok {
+		common = params
+		var parameters []interface{}
+		for _, param := range params {
+			if p, ok := c.parameter(param); ok && !isSwaggerBodyParameter(p) {
+				parameters = append(parameters, convertSwaggerParameter(p))
+			}
+		}
+		if len(parameters) > 0 {
+			pathItem["parameters"] = parameters
+		}
+	}
+	for _, method := range []string{"get", "put", "post", "delete", "options", "head", "patch"} {
+		if op, ok := item[method].(map[string]interface{}); ok {
+			pathItem[method] = c.operation(op, common)
+		}
+	}
+	return pathItem
+}
+
+// parameter returns the parameter, resolving the reference to the body
+// and formData parameters so that they are converted into request bodies.
+func (c *swaggerConverter) parameter(param interface{}) (map[string]interface{}, bool) {
+	p, ok := param.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	ref, ok := p["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/parameters/") {
+		return p, true
+	}
+	params, _ := c.swagger["parameters"].(map[string]interface{})
+	resolved, ok := params[jsonPointerUnescaper.Replace(strings.TrimPrefix(ref, "#/parameters/"))].(map[string]interface{})
+	if !ok {
+		return p, true
+	}
+	switch resolved["in"] {
+	case "body":
+		return map[string]interface{}{"in": "body", "$ref": ref}, true
+	case "formData":
+		return resolved, true
+	}
+	return p, true
+}
+
+func isSwaggerBodyParameter(p map[string]interface{}) bool {
+	return p["in"] == "body" || p["in"] == "formData"
+}
+
+func (c *swaggerConverter) operation(op map[string]interface{}, common []interface{}) map[string]interface{} {
+	operation := map[string]interface{}{}
+	for _, key := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if v, ok := op[key]; ok {
+			operation[key] = v
+		}
+	}
+	copyExtensions(operation, op)
+	consumes := mediaTypes(op, "consumes", c.consumes())
+	produces := mediaTypes(op, "produces", c.produces())
+
+	var body map[string]interface{}
+	var form []map[string]interface{}
+	var parameters []interface{}
+	params, _ := op["parameters"].([]interface{})
+	for _, param := range append(append([]interface{}{}, common...), params...) {
+		p, ok := c.parameter(param)
+		if !ok {
+			continue
+		}
+		switch p["in"] {
+		case "body":
+			body = p
+		case "formData":
+			form = append(form, p)
+		}
+	}
+	for _, param := range params {
+		if p, ok := c.parameter(param); ok && !isSwaggerBodyParameter(p) {
+			parameters = append(parameters, convertSwaggerParameter(p))
+		}
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	switch {
+	case body != nil:
+		operation["requestBody"] = c.requestBody(body, consumes)
+	case len(form) > 0:
+		operation["requestBody"] = formRequestBody(form, consumes)
+	}
+	responses := map[string]interface{}{}
+	if resps, ok := op["responses"].(map[string]interface{}); ok {
+		for status, resp := range resps {
+			if strings.HasPrefix(status, "x-") {
+				continue
+			}
+			responses[status] = c.response(resp, produces)
+		}
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+func (c *swaggerConverter) requestBody(p map[string]interface{}, consumes []string) map[string]interface{} {
+	if ref, ok := p["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/")}
+	}
+	content := map[string]interface{}{}
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]interface{}{"schema": convertSwaggerSchema(p["schema"])}
+	}
+	requestBody := map[string]interface{}{"content": content}
+	if desc, ok := p["description"]; ok {
+		requestBody["description"] = desc
+	}
+	if required, ok := p["required"].(bool); ok && required {
+		requestBody["required"] = true
+	}
+	return requestBody
+}
+
+func formRequestBody(form []map[string]interface{}, consumes []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []interface{}
+	mediaType := "application/x-www-form-urlencoded"
+	for _, p := range form {
+		name, _ := p["name"].(string)
+		schema := swaggerParameterSchema(p)
+		if desc, ok := p["description"]; ok {
+			schema["description"] = desc
+		}
+		properties[name] = schema
+		if r, ok := p["required"].(bool); ok && r {
+			required = append(required, name)
+		}
+		if p["type"] == "file" {
+			mediaType = "multipart/form-data"
+		}
+	}
+	if containsString(consumes, "multipart/form-data") {
+		mediaType = "multipart/form-data"
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return map[string]interface{}{
+		"content": map[string]interface{}{mediaType: map[string]interface{}{"schema": schema}},
+	}
+}
+
+func (c *swaggerConverter) response(resp interface{}, produces []string) interface{} {
+	r, ok := resp.(map[string]interface{})
+	if !ok {
+		return resp
+	}
+	if ref, ok := r["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": convertSwaggerRef(ref)}
+	}
+	response := map[string]interface{}{"description": r["description"]}
+	copyExtensions(response, r)
+	examples, _ := r["examples"].(map[string]interface{})
+	if schema, ok := r["schema"]; ok {
+		content := map[string]interface{}{}
+		for _, mediaType := range produces {
+			mt := map[string]interface{}{"schema": convertSwaggerSchema(schema)}
+			if example, ok := examples[mediaType]; ok {
+				mt["example"] = example
+			}
+			content[mediaType] = mt
+		}
+		response["content"] = content
+	}
+	if hs, ok := r["headers"].(map[string]interface{}); ok {
+		headers := map[string]interface{}{}
+		for name, h := range hs {
+			if header, ok := h.(map[string]interface{}); ok {
+				converted := map[string]interface{}{"schema": swaggerParameterSchema(header)}
+				if desc, ok := header["description"]; ok {
+					converted["description"] = desc
+				}
+				headers[name] = converted
+			}
+		}
+		response["headers"] = headers
+	}
+	return response
+}
+
+// swaggerSchemaKeys are the keys of the non-body parameters and the
+// headers which are moved into the schemas.
+var swaggerSchemaKeys = []string{"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf"}
+
+func swaggerParameterSchema(p map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{}
+	for _, key := range swaggerSchemaKeys {
+		if v, ok := p[key]; ok {
+			schema[key] = v
+		}
+	}
+	return convertSwaggerSchema(schema).(map[string]interface{})
+}
+
+func convertSwaggerParameter(p map[string]interface{}) map[string]interface{} {
+	if ref, ok := p["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": convertSwaggerRef(ref)}
+	}
+	param := map[string]interface{}{}
+	for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if v, ok := p[key]; ok {
+			param[key] = v
+		}
+	}
+	copyExtensions(param, p)
+	param["schema"] = swaggerParameterSchema(p)
+	switch p["collectionFormat"] {
+	case "csv":
+		param["style"] = "form"
+		if p["in"] != "query" {
+			param["style"] = "simple"
+		}
+		param["explode"] = false
+	case "ssv":
+		param["style"] = "spaceDelimited"
+	case "pipes":
+		param["style"] = "pipeDelimited"
+	case "multi":
+		param["style"] = "form"
+		param["explode"] = true
+	}
+	return param
+}
+
+// convertSwaggerSchema converts the schema: the references to the
+// definitions, the file type, x-nullable and the discriminator.
+func convertSwaggerSchema(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		schema := map[string]interface{}{}
+		for k, val := range c {
+			switch k {
+			case "$ref":
+				if ref, ok := val.(string); ok {
+					val = convertSwaggerRef(ref)
+				}
+			case "x-nullable":
+				k = "nullable"
+			case "discriminator":
+				if name, ok := val.(string); ok {
+					val = map[string]interface{}{"propertyName": name}
+				}
+			case "example", "default", "enum":
+			case "collectionFormat":
+				// the items of array parameters
+				continue
+			case "properties":
+				props := map[string]interface{}{}
+				if m, ok := val.(map[string]interface{}); ok {
+					for name, prop := range m {
+						props[name] = convertSwaggerSchema(prop)
+					}
+				}
+				val = props
+			default:
+				val = convertSwaggerSchema(val)
+			}
+			schema[k] = val
+		}
+		if schema["type"] == "file" {
+			schema["type"] = "string"
+			schema["format"] = "binary"
+		}
+		return schema
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, val := range c {
+			s[i] = convertSwaggerSchema(val)
+		}
+		return s
+	}
+	return v
+}
+
+var swaggerRefReplacer = strings.NewReplacer(
+	"#/definitions/", "#/components/schemas/",
+	"#/parameters/", "#/components/parameters/",
+	"#/responses/", "#/components/responses/",
+)
+
+func convertSwaggerRef(ref string) string {
+	return swaggerRefReplacer.Replace(ref)
+}
+
+func convertSwaggerSecurityScheme(def map[string]interface{}) map[string]interface{} {
+	scheme := map[string]interface{}{}
+	if desc, ok := def["description"]; ok {
+		scheme["description"] = desc
+	}
+	copyExtensions(scheme, def)
+	switch def["type"] {
+	case "basic":
+		scheme["type"] = "http"
+		scheme["scheme"] = "basic"
+	case "apiKey":
+		scheme["type"] = "apiKey"
+		scheme["name"] = def["name"]
+		scheme["in"] = def["in"]
+	case "oauth2":
+		scheme["type"] = "oauth2"
+		flow := map[string]interface{}{"scopes": def["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		if u, ok := def["authorizationUrl"]; ok {
+			flow["authorizationUrl"] = u
+		}
+		if u, ok := def["tokenUrl"]; ok {
+			flow["tokenUrl"] = u
+		}
+		flows := map[string]interface{}{}
+		switch def["flow"] {
+		case "implicit":
+			flows["implicit"] = flow
+		case "password":
+			flows["password"] = flow
+		case "application":
+			flows["clientCredentials"] = flow
+		case "accessCode":
+			flows["authorizationCode"] = flow
+		}
+		scheme["flows"] = flows
+	}
+	return scheme
+}
@@ -0,0 +1,193 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+const swaggerSpec = `
+swagger: "2.0"
+info:
+  title: swagger
+  version: 1.0.0
+host: api.example.com
+basePath: /v1
+schemes: [https, http]
+consumes: [application/json]
+produces: [application/json]
+x-audience: public
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - $ref: '#/parameters/Limit'
+        - name: tags
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: multi
+      responses:
+        '200':
+          description: pets
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/Pet'
+          headers:
+            X-Total:
+              type: integer
+              description: total count
+        default:
+          $ref: '#/responses/Error'
+    post:
+      operationId: createPet
+      parameters:
+        - $ref: '#/parameters/PetBody'
+      responses:
+        '201':
+          description: created
+  /pets/{id}/photo:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        type: string
+    post:
+      operationId: uploadPhoto
+      consumes: [multipart/form-data]
+      parameters:
+        - name: photo
+          in: formData
+          type: file
+          required: true
+        - name: caption
+          in: formData
+          type: string
+      responses:
+        '204':
+          description: uploaded
+parameters:
+  Limit:
+    name: limit
+    in: query
+    type: integer
+    maximum: 100
+  PetBody:
+    name: pet
+    in: body
+    required: true
+    schema:
+      $ref: '#/definitions/Pet'
+responses:
+  Error:
+    description: error
+    schema:
+      $ref: '#/definitions/Error'
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    required: [kind]
+    properties:
+      kind:
+        type: string
+      name:
+        type: string
+        x-nullable: true
+  Error:
+    type: object
+securityDefinitions:
+  basic:
+    type: basic
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/auth
+    tokenUrl: https://example.com/token
+    scopes:
+      read: read pets
+security:
+  - basic: []
+`
+
+func TestConvertSwagger(t *testing.T) {
+	doc, err := openapi.ConvertSwagger([]byte(swaggerSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "3.0.2" || doc.Info.Title != "swagger" {
+		t.Errorf("unexpected version or info: %s %+v", doc.Version, doc.Info)
+	}
+	var urls []string
+	for _, server := range doc.Servers {
+		urls = append(urls, server.URL)
+	}
+	if expected := []string{"https://api.example.com/v1", "http://api.example.com/v1"}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("%v != %v", urls, expected)
+	}
+
+	list := doc.Paths["/pets"].Get
+	if got := list.Parameters[0].Ref; got != "#/components/parameters/Limit" {
+		t.Errorf("%s != #/components/parameters/Limit", got)
+	}
+	tags := list.Parameters[1]
+	if tags.Schema.Type != "array" || tags.Schema.Items.Type != "string" || tags.Style != "form" || tags.Explode == nil || !*tags.Explode {
+		t.Errorf("unexpected parameter: %+v", tags)
+	}
+	ok := list.Responses["200"]
+	if got := ok.Content["application/json"].Schema.Items.Ref; got != "#/components/schemas/Pet" {
+		t.Errorf("%s != #/components/schemas/Pet", got)
+	}
+	if got := ok.Headers["X-Total"]; got.Schema.Type != "integer" || got.Description != "total count" {
+		t.Errorf("unexpected header: %+v", got)
+	}
+	if got := list.Responses["default"].Ref; got != "#/components/responses/Error" {
+		t.Errorf("%s != #/components/responses/Error", got)
+	}
+
+	if got := doc.Paths["/pets"].Post.RequestBody.Ref; got != "#/components/requestBodies/PetBody" {
+		t.Errorf("%s != #/components/requestBodies/PetBody", got)
+	}
+	petBody := doc.Components.RequestBodies["PetBody"]
+	if !petBody.Required || petBody.Content["application/json"].Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("unexpected request body: %+v", petBody)
+	}
+
+	photo := doc.Paths["/pets/{id}/photo"]
+	if len(photo.Parameters) != 1 || photo.Parameters[0].Schema.Type != "string" {
+		t.Errorf("unexpected path parameters: %+v", photo.Parameters)
+	}
+	form := photo.Post.RequestBody.Content["multipart/form-data"].Schema
+	if form.Properties["photo"].Type != "string" || form.Properties["photo"].Format != "binary" || !reflect.DeepEqual(form.Required, []string{"photo"}) {
+		t.Errorf("unexpected form schema: %+v", form)
+	}
+
+	pet := doc.Components.Schemas["Pet"]
+	if pet.Discriminator.PropertyName != "kind" || !pet.Properties["name"].Nullable {
+		t.Errorf("unexpected schema: %+v", pet)
+	}
+	if got := doc.Components.Parameters["Limit"].Schema.Maximum; got != "100" {
+		t.Errorf("%s != 100", got)
+	}
+	basic := doc.Components.SecuritySchemes["basic"]
+	if basic.Type != "http" || basic.Scheme != "basic" {
+		t.Errorf("unexpected security scheme: %+v", basic)
+	}
+	oauth := doc.Components.SecuritySchemes["oauth"]
+	if oauth.Flows.AuthorizationCode == nil || oauth.Flows.AuthorizationCode.TokenURL != "https://example.com/token" {
+		t.Errorf("unexpected security scheme: %+v", oauth)
+	}
+}
+
+func TestConvertSwagger_UnsupportedVersion(t *testing.T) {
+	if _, err := openapi.ConvertSwagger([]byte(pruneSpec)); err != openapi.ErrUnsupportedVersion {
+		t.Errorf("%v != %v", err, openapi.ErrUnsupportedVersion)
+	}
+}
@@ -4,9 +4,9 @@ package openapi
 
 // Tag Object
 type Tag struct {
-	Name         string
-	Description  string
-	ExternalDocs *ExternalDocumentation `yaml:"externalDocs"`
+	Name         string                 `yaml:"name,omitempty"`
+	Description  string                 `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocumentation `yaml:"externalDocs,omitempty"`
 }
 
 // Validate the values of Tag object.
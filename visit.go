@@ -0,0 +1,507 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VisitFunc is the type of the function called for each object visited
+// by Document.Visit. The pointer is the JSON pointer of the node in the
+// document, and the parent is the object which contains the node.
+// The node is a pointer to the object, like *Schema, so the function can
+// modify it in place; the children are visited after the modification.
+// If the function returns SkipNode, the children of the node are skipped.
+// If the function returns other non-nil error, Visit stops and returns it.
+type VisitFunc func(pointer string, node, parent interface{}) error
+
+// SkipNode is used as a return value from VisitFunc to indicate that
+// the children of the node are to be skipped.
+const SkipNode errString = "skip this node"
+
+// Visit visits all the objects in the document in depth-first order,
+// calling fn for each object including the document itself.
+// The maps are visited in order of the keys. The references are not
+// followed; the reference objects are visited as they are.
+// The schemas shared in the document are visited at each location, except
+// for the schemas which contain themselves.
+func (doc *Document) Visit(fn VisitFunc) error {
+	v := &visitor{fn: fn, ancestors: map[*Schema]bool{}}
+	return v.document(doc)
+}
+
+type visitor struct {
+	fn        VisitFunc
+	ancestors map[*Schema]bool
+}
+
+func (v *visitor) visit(ptr string, node, parent interface{}, children func() error) error {
+	if err := v.fn(ptr, node, parent); err != nil {
+		if err == SkipNode {
+			return nil
+		}
+		return err
+	}
+	return children()
+}
+
+func childPointer(ptr, key string) string {
+	return ptr + "/" + jsonPointerEscaper.Replace(key)
+}
+
+func indexPointer(ptr, key string, i int) string {
+	return childPointer(childPointer(ptr, key), strconv.Itoa(i))
+}
+
+func (v *visitor) document(doc *Document) error {
+	return v.visit("", doc, nil, func() error {
+		if doc.Info != nil {
+			if err := v.info("/info", doc.Info, doc); err != nil {
+				return err
+			}
+		}
+		if err := v.servers("", doc.Servers, doc); err != nil {
+			return err
+		}
+		for _, path := range sortedMapKeys(doc.Paths) {
+			if err := v.pathItem(childPointer("/paths", path), doc.Paths[path], doc); err != nil {
+				return err
+			}
+		}
+		if doc.Components != nil {
+			if err := v.components("/components", doc.Components, doc); err != nil {
+				return err
+			}
+		}
+		if err := v.securityRequirements("", doc.Security, doc); err != nil {
+			return err
+		}
+		for i, tag := range doc.Tags {
+			if tag == nil {
+				continue
+			}
+			if err := v.tag(indexPointer("", "tags", i), tag, doc); err != nil {
+				return err
+			}
+		}
+		return v.externalDocs("/externalDocs", doc.ExternalDocs, doc)
+	})
+}
+
+func (v *visitor) info(ptr string, info *Info, parent interface{}) error {
+	return v.visit(ptr, info, parent, func() error {
+		if info.Contact != nil {
+			if err := v.fn(ptr+"/contact", info.Contact, info); err != nil && err != SkipNode {
+				return err
+			}
+		}
+		if info.License != nil {
+			if err := v.fn(ptr+"/license", info.License, info); err != nil && err != SkipNode {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *visitor) servers(ptr string, servers []*Server, parent interface{}) error {
+	for i, server := range servers {
+		if server == nil {
+			continue
+		}
+		if err := v.server(indexPointer(ptr, "servers", i), server, parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *visitor) server(ptr string, server *Server, parent interface{}) error {
+	return v.visit(ptr, server, parent, func() error {
+		for _, name := range sortedMapKeys(server.Variables) {
+			if err := v.fn(childPointer(ptr+"/variables", name), server.Variables[name], server); err != nil && err != SkipNode {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *visitor) tag(ptr string, tag *Tag, parent interface{}) error {
+	return v.visit(ptr, tag, parent, func() error {
+		return v.externalDocs(ptr+"/externalDocs", tag.ExternalDocs, tag)
+	})
+}
+
+func (v *visitor) externalDocs(ptr string, externalDocs *ExternalDocumentation, parent interface{}) error {
+	if externalDocs == nil {
+		return nil
+	}
+	if err := v.fn(ptr, externalDocs, parent); err != nil && err != SkipNode {
+		return err
+	}
+	return nil
+}
+
+func (v *visitor) securityRequirements(ptr string, secReqs []*SecurityRequirement, parent interface{}) error {
+	for i, secReq := range secReqs {
+		if secReq == nil {
+			continue
+		}
+		if err := v.fn(indexPointer(ptr, "security", i), secReq, parent); err != nil && err != SkipNode {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *visitor) pathItem(ptr string, pathItem *PathItem, parent interface{}) error {
+	if pathItem == nil {
+		return nil
+	}
+	return v.visit(ptr, pathItem, parent, func() error {
+		for _, method := range methods {
+			op := pathItem.GetOperationByMethod(method)
+			if op == nil {
+				continue
+			}
+			if err := v.operation(childPointer(ptr, strings.ToLower(method)), op, pathItem); err != nil {
+				return err
+			}
+		}
+		if err := v.servers(ptr, pathItem.Servers, pathItem); err != nil {
+			return err
+		}
+		return v.parameters(ptr, pathItem.Parameters, pathItem)
+	})
+}
+
+func (v *visitor) operation(ptr string, op *Operation, parent interface{}) error {
+	return v.visit(ptr, op, parent, func() error {
+		if err := v.externalDocs(ptr+"/externalDocs", op.ExternalDocs, op); err != nil {
+			return err
+		}
+		if err := v.parameters(ptr, op.Parameters, op); err != nil {
+			return err
+		}
+		if op.RequestBody != nil {
+			if err := v.requestBody(ptr+"/requestBody", op.RequestBody, op); err != nil {
+				return err
+			}
+		}
+		for _, status := range sortedMapKeys(op.Responses) {
+			if err := v.response(childPointer(ptr+"/responses", status), op.Responses[status], op); err != nil {
+				return err
+			}
+		}
+		for _, name := range sortedMapKeys(op.Callbacks) {
+			if err := v.callback(childPointer(ptr+"/callbacks", name), op.Callbacks[name], op); err != nil {
+				return err
+			}
+		}
+		if err := v.securityRequirements(ptr, op.securityRequirements(), op); err != nil {
+			return err
+		}
+		return v.servers(ptr, op.Servers, op)
+	})
+}
+
+func (v *visitor) parameters(ptr string, parameters []*Parameter, parent interface{}) error {
+	for i, parameter := range parameters {
+		if err := v.parameter(indexPointer(ptr, "parameters", i), parameter, parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *visitor) parameter(ptr string, parameter *Parameter, parent interface{}) error {
+	if parameter == nil {
+		return nil
+	}
+	return v.visit(ptr, parameter, parent, func() error {
+		if err := v.schema(ptr+"/schema", parameter.Schema, parameter); err != nil {
+			return err
+		}
+		if err := v.examples(ptr, parameter.Examples, parameter); err != nil {
+			return err
+		}
+		return v.content(ptr, parameter.Content, parameter)
+	})
+}
+
+func (v *visitor) header(ptr string, header *Header, parent interface{}) error {
+	if header == nil {
+		return nil
+	}
+	return v.visit(ptr, header, parent, func() error {
+		if err := v.schema(ptr+"/schema", header.Schema, header); err != nil {
+			return err
+		}
+		if err := v.examples(ptr, header.Examples, header); err != nil {
+			return err
+		}
+		return v.content(ptr, header.Content, header)
+	})
+}
+
+func (v *visitor) headers(ptr string, headers map[string]*Header, parent interface{}) error {
+	for _, name := range sortedMapKeys(headers) {
+		if err := v.header(childPointer(ptr+"/headers", name), headers[name], parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *visitor) requestBody(ptr string, requestBody *RequestBody, parent interface{}) error {
+	if requestBody == nil {
+		return nil
+	}
+	return v.visit(ptr, requestBody, parent, func() error {
+		return v.content(ptr, requestBody.Content, requestBody)
+	})
+}
+
+func (v *visitor) response(ptr string, response *Response, parent interface{}) error {
+	if response == nil {
+		return nil
+	}
+	return v.visit(ptr, response, parent, func() error {
+		if err := v.headers(ptr, response.Headers, response); err != nil {
+			return err
+		}
+		if err := v.content(ptr, response.Content, response); err != nil {
+			return err
+		}
+		for _, name := range sortedMapKeys(response.Links) {
+			if err := v.link(childPointer(ptr+"/links", name), response.Links[name], response); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *visitor) content(ptr string, content map[string]*MediaType, parent interface{}) error {
+	for _, mt := range sortedMapKeys(content) {
+		if err := v.mediaType(childPointer(ptr+"/content", mt), content[mt], parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *visitor) mediaType(ptr string, mediaType *MediaType, parent interface{}) error {
+	if mediaType == nil {
+		return nil
+	}
+	return v.visit(ptr, mediaType, parent, func() error {
+		if err := v.schema(ptr+"/schema", mediaType.Schema, mediaType); err != nil {
+			return err
+		}
+		if err := v.examples(ptr, mediaType.Examples, mediaType); err != nil {
+			return err
+		}
+		for _, name := range sortedMapKeys(mediaType.Encoding) {
+			encoding := mediaType.Encoding[name]
+			if encoding == nil {
+				continue
+			}
+			encodingPtr := childPointer(ptr+"/encoding", name)
+			err := v.visit(encodingPtr, encoding, mediaType, func() error {
+				return v.headers(encodingPtr, encoding.Headers, encoding)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *visitor) examples(ptr string, examples map[string]*Example, parent interface{}) error {
+	for _, name := range sortedMapKeys(examples) {
+		example := examples[name]
+		if example == nil {
+			continue
+		}
+		if err := v.fn(childPointer(ptr+"/examples", name), example, parent); err != nil && err != SkipNode {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *visitor) link(ptr string, link *Link, parent interface{}) error {
+	if link == nil {
+		return nil
+	}
+	return v.visit(ptr, link, parent, func() error {
+		if link.Server != nil {
+			return v.server(ptr+"/server", link.Server, link)
+		}
+		return nil
+	})
+}
+
+func (v *visitor) callback(ptr string, callback *Callback, parent interface{}) error {
+	if callback == nil {
+		return nil
+	}
+	return v.visit(ptr, callback, parent, func() error {
+		var exprs []string
+		for expr := range *callback {
+			exprs = append(exprs, expr)
+		}
+		sort.Strings(exprs)
+		for _, expr := range exprs {
+			if err := v.pathItem(childPointer(ptr, expr), (*callback)[expr], callback); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *visitor) schema(ptr string, schema *Schema, parent interface{}) error {
+	if schema == nil || v.ancestors[schema] {
+		return nil
+	}
+	v.ancestors[schema] = true
+	defer delete(v.ancestors, schema)
+	return v.visit(ptr, schema, parent, func() error {
+		children := []struct {
+			key     string
+			schemas []*Schema
+		}{
+			{"allOf", schema.AllOf},
+			{"oneOf", schema.OneOf},
+			{"anyOf", schema.AnyOf},
+		}
+		for _, c := range children {
+			for i, s := range c.schemas {
+				if err := v.schema(indexPointer(ptr, c.key, i), s, schema); err != nil {
+					return err
+				}
+			}
+		}
+		if err := v.schema(ptr+"/not", schema.Not, schema); err != nil {
+			return err
+		}
+		if err := v.schema(ptr+"/items", schema.Items, schema); err != nil {
+			return err
+		}
+		for _, name := range sortedMapKeys(schema.Properties) {
+			if err := v.schema(childPointer(ptr+"/properties", name), schema.Properties[name], schema); err != nil {
+				return err
+			}
+		}
+		if err := v.schema(ptr+"/additionalProperties", schema.AdditionalProperties, schema); err != nil {
+			return err
+		}
+		if schema.Discriminator != nil {
+			if err := v.fn(ptr+"/discriminator", schema.Discriminator, schema); err != nil && err != SkipNode {
+				return err
+			}
+		}
+		if schema.XML != nil {
+			if err := v.fn(ptr+"/xml", schema.XML, schema); err != nil && err != SkipNode {
+				return err
+			}
+		}
+		return v.externalDocs(ptr+"/externalDocs", schema.ExternalDocs, schema)
+	})
+}
+
+func (v *visitor) components(ptr string, components *Components, parent interface{}) error {
+	return v.visit(ptr, components, parent, func() error {
+		for _, name := range sortedMapKeys(components.Schemas) {
+			if err := v.schema(childPointer(ptr+"/schemas", name), components.Schemas[name], components); err != nil {
+				return err
+			}
+		}
+		for _, name := range sortedMapKeys(components.Responses) {
+			if err := v.response(childPointer(ptr+"/responses", name), components.Responses[name], components); err != nil {
+				return err
+			}
+		}
+		for _, name := range sortedMapKeys(components.Parameters) {
+			if err := v.parameter(childPointer(ptr+"/parameters", name), components.Parameters[name], components); err != nil {
+				return err
+			}
+		}
+		if err := v.examples(ptr, components.Examples, components); err != nil {
+			return err
+		}
+		for _, name := range sortedMapKeys(components.RequestBodies) {
+			if err := v.requestBody(childPointer(ptr+"/requestBodies", name), components.RequestBodies[name], components); err != nil {
+				return err
+			}
+		}
+		if err := v.headers(ptr, components.Headers, components); err != nil {
+			return err
+		}
+		for _, name := range sortedMapKeys(components.SecuritySchemes) {
+			if err := v.securityScheme(childPointer(ptr+"/securitySchemes", name), components.SecuritySchemes[name], components); err != nil {
+				return err
+			}
+		}
+		for _, name := range sortedMapKeys(components.Links) {
+			if err := v.link(childPointer(ptr+"/links", name), components.Links[name], components); err != nil {
+				return err
+			}
+		}
+		for _, name := range sortedMapKeys(components.Callbacks) {
+			if err := v.callback(childPointer(ptr+"/callbacks", name), components.Callbacks[name], components); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *visitor) securityScheme(ptr string, securityScheme *SecurityScheme, parent interface{}) error {
+	if securityScheme == nil {
+		return nil
+	}
+	return v.visit(ptr, securityScheme, parent, func() error {
+		flows := securityScheme.Flows
+		if flows == nil {
+			return nil
+		}
+		flowsPtr := ptr + "/flows"
+		return v.visit(flowsPtr, flows, securityScheme, func() error {
+			children := []struct {
+				key  string
+				flow *OAuthFlow
+			}{
+				{"implicit", flows.Implicit},
+				{"password", flows.Password},
+				{"clientCredentials", flows.ClientCredentials},
+				{"authorizationCode", flows.AuthorizationCode},
+			}
+			for _, c := range children {
+				if c.flow == nil {
+					continue
+				}
+				if err := v.fn(flowsPtr+"/"+c.key, c.flow, flows); err != nil && err != SkipNode {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// sortedMapKeys returns the sorted keys of the map which has string keys.
+func sortedMapKeys(m interface{}) []string {
+	rv := reflect.ValueOf(m)
+	keys := make([]string, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+package openapi_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestDocument_Visit(t *testing.T) {
+	doc, err := openapi.LoadFile("test/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pointers []string
+	parents := map[string]interface{}{}
+	err = doc.Visit(func(ptr string, node, parent interface{}) error {
+		pointers = append(pointers, ptr)
+		parents[ptr] = parent
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"",
+		"/info",
+		"/info/license",
+		"/servers/0",
+		"/paths/~1pets",
+		"/paths/~1pets/get",
+		"/paths/~1pets/get/parameters/0",
+		"/paths/~1pets/get/parameters/0/schema",
+		"/paths/~1pets/get/responses/200",
+		"/paths/~1pets/get/responses/200/headers/x-next",
+		"/paths/~1pets/get/responses/200/headers/x-next/schema",
+		"/paths/~1pets/get/responses/200/content/application~1json",
+		"/paths/~1pets/get/responses/200/content/application~1json/schema",
+		"/paths/~1pets/get/responses/default",
+		"/paths/~1pets/get/responses/default/content/application~1json",
+		"/paths/~1pets/get/responses/default/content/application~1json/schema",
+	}
+	if !reflect.DeepEqual(pointers[:len(expected)], expected) {
+		t.Errorf("unexpected pointers:\n  got:\t%v\n  want:\t%v", pointers[:len(expected)], expected)
+	}
+	for _, ptr := range []string{
+		"/components",
+		"/components/schemas/Pet",
+		"/components/schemas/Pet/properties/id",
+		"/components/schemas/Pets/items",
+	} {
+		if _, ok := parents[ptr]; !ok {
+			t.Errorf("%s is not visited", ptr)
+		}
+	}
+	if parents["/paths/~1pets/get"] != doc.Paths["/pets"] {
+		t.Error("parent of operation should be the path item")
+	}
+	if parents["/components/schemas/Pets/items"] != doc.Components.Schemas["Pets"] {
+		t.Error("parent of items should be the array schema")
+	}
+}
+
+func TestDocument_Visit_SkipAndStop(t *testing.T) {
+	doc, err := openapi.LoadFile("test/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.Visit(func(ptr string, node, parent interface{}) error {
+		if strings.HasPrefix(ptr, "/paths/") {
+			if _, ok := node.(*openapi.PathItem); !ok {
+				t.Errorf("children of path item should be skipped: %s", ptr)
+			}
+			return openapi.SkipNode
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := errors.New("stop")
+	var count int
+	err = doc.Visit(func(ptr string, node, parent interface{}) error {
+		count++
+		if _, ok := node.(*openapi.Operation); ok {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("%v != %v", err, stop)
+	}
+	if count != 6 {
+		t.Errorf("%d != 6", count)
+	}
+}
+
+func TestDocument_Visit_Mutation(t *testing.T) {
+	doc, err := openapi.LoadFile("test/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.Visit(func(ptr string, node, parent interface{}) error {
+		if schema, ok := node.(*openapi.Schema); ok && schema.Type == "integer" {
+			schema.Description = "integer"
+		}
+		if op, ok := node.(*openapi.Operation); ok {
+			op.Deprecated = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Paths["/pets"].Get.Deprecated {
+		t.Error("operation should be modified")
+	}
+	if got := doc.Components.Schemas["Pet"].Properties["id"].Description; got != "integer" {
+		t.Errorf("%s != integer", got)
+	}
+}
+
+func TestDocument_Visit_SharedSchema(t *testing.T) {
+	shared := &openapi.Schema{Type: "string"}
+	pet := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"name":     shared,
+			"nickname": shared,
+		},
+	}
+	pet.Properties["parent"] = pet
+	doc := &openapi.Document{
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{"Pet": pet},
+		},
+	}
+	var pointers []string
+	err := doc.Visit(func(ptr string, node, parent interface{}) error {
+		if _, ok := node.(*openapi.Schema); ok {
+			pointers = append(pointers, ptr)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"/components/schemas/Pet",
+		"/components/schemas/Pet/properties/name",
+		"/components/schemas/Pet/properties/nickname",
+	}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("%v != %v", pointers, expected)
+	}
+}
@@ -4,11 +4,11 @@ package openapi
 
 // XML Object
 type XML struct {
-	Name      string
-	Namespace string
-	Prefix    string
-	Attribute bool
-	Wrapped   bool
+	Name      string `yaml:"name,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+	Prefix    string `yaml:"prefix,omitempty"`
+	Attribute bool   `yaml:"attribute,omitempty"`
+	Wrapped   bool   `yaml:"wrapped,omitempty"`
 }
 
 // Validate the values of XML object.
@@ -0,0 +1,337 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// EncodeXML encodes given value as XML according to the schema and its XML objects.
+// The name of the root element is the xml.name of the schema, the component
+// name if the schema is a reference, or given name in this order.
+// The value should be a decoded JSON value, or a value which can be
+// converted via encoding/json.
+func EncodeXML(root *Document, schema *Schema, name string, value interface{}) ([]byte, error) {
+	v, err := normalizeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	e := xmlEncoder{root: root}
+	if err := e.encode(schema, name, v, true); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// DecodeXML decodes given XML document according to the schema and its XML objects.
+// Objects are decoded as map[string]interface{} and arrays are decoded as
+// []interface{}. Primitive values are coerced by the schema type.
+// The elements are matched by the local name, ignoring the namespace.
+func DecodeXML(root *Document, schema *Schema, data []byte) (interface{}, error) {
+	node, err := parseXMLNode(data)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveSchema(root, schema)
+	if err != nil {
+		return nil, err
+	}
+	if resolved != nil && resolved.Type == "array" {
+		return decodeXMLItems(root, resolved, node.children, "")
+	}
+	return decodeXMLNode(root, schema, node)
+}
+
+// refName returns the component name if the schema is a reference.
+func refName(schema *Schema) string {
+	if schema == nil || schema.Ref == "" {
+		return ""
+	}
+	return schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+}
+
+// xmlElementName returns the local name of the element for the schema.
+func xmlElementName(schema, resolved *Schema, fallback string) string {
+	name := fallback
+	if name == "" {
+		name = refName(schema)
+	}
+	if resolved != nil && resolved.XML != nil && resolved.XML.Name != "" {
+		name = resolved.XML.Name
+	}
+	return name
+}
+
+type xmlEncoder struct {
+	root *Document
+	buf  bytes.Buffer
+}
+
+func (e *xmlEncoder) escape(s string) {
+	xml.EscapeText(&e.buf, []byte(s))
+}
+
+// qualifiedName returns the element name with prefix and the
+// namespace declaration attribute.
+func qualifiedName(x *XML, name string) (string, string) {
+	if x == nil {
+		return name, ""
+	}
+	var ns string
+	qname := name
+	if x.Prefix != "" {
+		qname = x.Prefix + ":" + name
+		if x.Namespace != "" {
+			ns = "xmlns:" + x.Prefix
+		}
+	} else if x.Namespace != "" {
+		ns = "xmlns"
+	}
+	return qname, ns
+}
+
+func (e *xmlEncoder) encode(schema *Schema, fallback string, value interface{}, isRoot bool) error {
+	resolved, err := resolveSchema(e.root, schema)
+	if err != nil {
+		return err
+	}
+	if resolved == nil {
+		resolved = &Schema{}
+	}
+	name := xmlElementName(schema, resolved, fallback)
+	if name == "" {
+		name = "root"
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		wrapped := isRoot || (resolved.XML != nil && resolved.XML.Wrapped)
+		if wrapped {
+			e.openElement(resolved.XML, name, nil)
+		}
+		for _, item := range items {
+			if err := e.encode(resolved.Items, name, item, false); err != nil {
+				return err
+			}
+		}
+		if wrapped {
+			e.closeElement(resolved.XML, name)
+		}
+		return nil
+	}
+
+	obj, isObject := value.(map[string]interface{})
+	var attrs []xml.Attr
+	var children []string
+	if isObject {
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			propSchema, err := resolveSchema(e.root, resolved.Properties[k])
+			if err != nil {
+				return err
+			}
+			if propSchema != nil && propSchema.XML != nil && propSchema.XML.Attribute {
+				attrName, _ := qualifiedName(propSchema.XML, xmlElementName(nil, propSchema, k))
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: attrName}, Value: stringifyValue(obj[k])})
+				continue
+			}
+			children = append(children, k)
+		}
+	}
+	e.openElement(resolved.XML, name, attrs)
+	switch {
+	case isObject:
+		for _, k := range children {
+			propSchema := resolved.Properties[k]
+			if propSchema == nil {
+				propSchema = resolved.AdditionalProperties
+			}
+			if obj[k] == nil {
+				continue
+			}
+			if err := e.encode(propSchema, k, obj[k], false); err != nil {
+				return err
+			}
+		}
+	case value != nil:
+		e.escape(stringifyValue(value))
+	}
+	e.closeElement(resolved.XML, name)
+	return nil
+}
+
+func (e *xmlEncoder) openElement(x *XML, name string, attrs []xml.Attr) {
+	qname, ns := qualifiedName(x, name)
+	e.buf.WriteString("<" + qname)
+	if ns != "" {
+		e.buf.WriteString(" " + ns + `="`)
+		e.escape(x.Namespace)
+		e.buf.WriteString(`"`)
+	}
+	for _, attr := range attrs {
+		e.buf.WriteString(" " + attr.Name.Local + `="`)
+		e.escape(attr.Value)
+		e.buf.WriteString(`"`)
+	}
+	e.buf.WriteString(">")
+}
+
+func (e *xmlEncoder) closeElement(x *XML, name string) {
+	qname, _ := qualifiedName(x, name)
+	e.buf.WriteString("</" + qname + ">")
+}
+
+// xmlNode is a generic tree of XML element.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+func parseXMLNode(data []byte) (*xmlNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name, attrs: t.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, ErrFormatInvalid{Target: "xml document"}
+	}
+	return root, nil
+}
+
+func decodeXMLNode(root *Document, schema *Schema, node *xmlNode) (interface{}, error) {
+	resolved, err := resolveSchema(root, schema)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == nil || (resolved.Type != "object" && len(resolved.Properties) == 0) {
+		return coercePrimitive(strings.TrimSpace(node.text), resolved, node.name.Local)
+	}
+	obj := map[string]interface{}{}
+	keys := make([]string, 0, len(resolved.Properties))
+	for k := range resolved.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		propSchema, err := resolveSchema(root, resolved.Properties[k])
+		if err != nil {
+			return nil, err
+		}
+		if propSchema == nil {
+			propSchema = &Schema{}
+		}
+		name := xmlElementName(nil, propSchema, k)
+		switch {
+		case propSchema.XML != nil && propSchema.XML.Attribute:
+			for _, attr := range node.attrs {
+				if attr.Name.Local == name {
+					v, err := coercePrimitive(attr.Value, propSchema, name)
+					if err != nil {
+						return nil, err
+					}
+					obj[k] = v
+				}
+			}
+		case propSchema.Type == "array":
+			children := node.children
+			itemName := name
+			if propSchema.XML != nil && propSchema.XML.Wrapped {
+				wrapper := findXMLChild(node, name)
+				if wrapper == nil {
+					continue
+				}
+				children = wrapper.children
+				itemName = ""
+			}
+			items, err := decodeXMLItems(root, propSchema, children, itemName)
+			if err != nil {
+				return nil, err
+			}
+			if items != nil {
+				obj[k] = items
+			}
+		default:
+			child := findXMLChild(node, name)
+			if child == nil {
+				continue
+			}
+			v, err := decodeXMLNode(root, resolved.Properties[k], child)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
+	}
+	return obj, nil
+}
+
+// decodeXMLItems decodes the items of an array.
+// If name is empty, all the children are items, otherwise the children
+// which have the name are items.
+func decodeXMLItems(root *Document, schema *Schema, children []*xmlNode, name string) ([]interface{}, error) {
+	itemSchema, err := resolveSchema(root, schema.Items)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		name = xmlElementName(nil, itemSchema, name)
+	}
+	var items []interface{}
+	for _, child := range children {
+		if name != "" && child.name.Local != name {
+			continue
+		}
+		v, err := decodeXMLNode(root, schema.Items, child)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func findXMLChild(node *xmlNode, name string) *xmlNode {
+	for _, child := range node.children {
+		if child.name.Local == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// isXMLMediaType reports whether the media type is XML.
+func isXMLMediaType(mt string) bool {
+	return mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml")
+}
@@ -0,0 +1,84 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	openapi "github.com/naoyamaguchi/go-openapi"
+)
+
+func TestXMLCodec(t *testing.T) {
+	doc := &openapi.Document{
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Person": &openapi.Schema{
+					Type: "object",
+					Properties: map[string]*openapi.Schema{
+						"id": &openapi.Schema{Type: "integer", XML: &openapi.XML{Attribute: true}},
+						"name": &openapi.Schema{
+							Type: "string",
+							XML:  &openapi.XML{Namespace: "http://example.com/schema/sample", Prefix: "sample"},
+						},
+						"animals": &openapi.Schema{
+							Type:  "array",
+							Items: &openapi.Schema{Type: "string", XML: &openapi.XML{Name: "animal"}},
+							XML:   &openapi.XML{Wrapped: true},
+						},
+						"tags":   &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+						"active": &openapi.Schema{Type: "boolean"},
+					},
+				},
+			},
+		},
+	}
+	schema := &openapi.Schema{Ref: "#/components/schemas/Person"}
+	value := map[string]interface{}{
+		"id":      int64(123),
+		"name":    "example <1>",
+		"animals": []interface{}{"cat", "dog"},
+		"tags":    []interface{}{"a", "b"},
+		"active":  true,
+	}
+	b, err := openapi.EncodeXML(doc, schema, "", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `<Person id="123"><active>true</active><animals><animal>cat</animal><animal>dog</animal></animals>` +
+		`<sample:name xmlns:sample="http://example.com/schema/sample">example &lt;1&gt;</sample:name><tags>a</tags><tags>b</tags></Person>`
+	if string(b) != expect {
+		t.Errorf("%s != %s", b, expect)
+	}
+	decoded, err := openapi.DecodeXML(doc, schema, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("%#v != %#v", decoded, value)
+	}
+}
+
+func TestXMLCodec_Array(t *testing.T) {
+	schema := &openapi.Schema{
+		Type:  "array",
+		Items: &openapi.Schema{Type: "integer", XML: &openapi.XML{Name: "id"}},
+		XML:   &openapi.XML{Name: "ids"},
+	}
+	b, err := openapi.EncodeXML(nil, schema, "", []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "<ids><id>1</id><id>2</id></ids>"; string(b) != expect {
+		t.Errorf("%s != %s", b, expect)
+	}
+	mediaType := openapi.MediaType{Schema: schema}
+	decoded, err := mediaType.Decode(nil, "application/xml", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []interface{}{int64(1), int64(2)}; !reflect.DeepEqual(decoded, expect) {
+		t.Errorf("%#v != %#v", decoded, expect)
+	}
+	if _, err := openapi.DecodeXML(nil, schema, []byte("<ids><id>a</id></ids>")); err == nil {
+		t.Error("error should occur")
+	}
+}